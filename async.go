@@ -0,0 +1,20 @@
+package future
+
+// ----------------------------------------------------------------------------
+// Async
+// ----------------------------------------------------------------------------
+
+// future.Async runs fn on a new goroutine and returns a future for its
+// result. The (value, error) returned by fn is provided per SetValue |
+// SetError, and a panic in fn is recovered and provided as a *PanicError
+// error result carrying the panic value and stack trace. A nil value,
+// per the future.Provider contract, is provided as an error result that
+// errors.Is future.ErrNilValue.
+//
+// Given an untyped fn - func() (interface{}, error) - the returned future
+// is a future.Future.
+func Async[T any](fn func() (T, error)) TypedFuture[T] {
+	f := NewFuture[T]()
+	go func() { provide[T](f)(try(fn)) }()
+	return f
+}
@@ -0,0 +1,83 @@
+/* white box tests */
+
+package future
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Async of fn returning a value
+// MUST result in the value
+func TestAsyncValue(t *testing.T) {
+	test := testSpec()
+
+	var future Future = Async(func() (interface{}, error) {
+		time.Sleep(test.providerDelay)
+		return test.data, nil
+	})
+
+	result, timeout := future.TryGet(time.Second)
+	switch {
+	case timeout:
+		t.Fatal("expected result by now")
+	case result.IsError():
+		t.Errorf("unexpected error %s", result.Error())
+	case bytes.Compare(result.Value().([]byte), test.data) != 0:
+		t.Error("unexpected result value")
+	}
+}
+
+// Async of fn returning an error
+// MUST result in the error
+func TestAsyncError(t *testing.T) {
+	test := testSpec()
+
+	future := Async(func() (int, error) { return 0, test.err })
+	if result := future.Get(); result.Error() != test.err {
+		t.Errorf("expected spec error - got %v", result.Error())
+	}
+}
+
+// Async of panicking fn
+// MUST result in a *PanicError with the panic value and stack trace
+func TestAsyncPanic(t *testing.T) {
+	test := testSpec()
+
+	future := Async(func() ([]byte, error) { panic(test.err) })
+
+	var pe *PanicError
+	result := future.Get()
+	switch {
+	case !errors.As(result.Error(), &pe):
+		t.Fatalf("expected *PanicError - got %v", result.Error())
+	case pe.Value != test.err:
+		t.Errorf("unexpected panic value %v", pe.Value)
+	case !errors.Is(result.Error(), test.err):
+		t.Error("expected panic error value to be unwrapped")
+	case !strings.Contains(string(pe.Stack), "TestAsyncPanic"):
+		t.Errorf("expected stack trace of panic - got %s", pe.Stack)
+	}
+}
+
+// Async of a blocking fn
+// MUST return (a future not set) without waiting for fn
+func TestAsyncNonBlocking(t *testing.T) {
+	test := testSpec()
+
+	release := make(chan struct{})
+	future := Async(func() (interface{}, error) {
+		<-release
+		return test.data, nil
+	})
+	if _, timeout := future.TryGet(0); !timeout {
+		t.Fatal("expected future not set before fn returns")
+	}
+	close(release)
+	if result, timeout := future.TryGet(time.Second); timeout || result.IsError() {
+		t.Errorf("expected value result - got %v", result)
+	}
+}
@@ -0,0 +1,295 @@
+package future
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+/* The lock-free implementation of Future (api) - untyped and typed */
+
+// atomicFuture states
+const (
+	statePending    uint32 = iota // not set
+	stateSetting                  // SetValue | SetError in progress
+	stateSet                      // set
+	stateCancelling               // Cancel in progress
+	stateCancelled                // cancelled
+)
+
+// ----------------------------------------------------------------------------
+// Pending Callbacks
+// ----------------------------------------------------------------------------
+
+// callbackNode is a callback registered (per OnComplete) on a future.
+type callbackNode[T any] struct {
+	fn   func(TypedResult[T])
+	next *callbackNode[T] // immutable once pushed, until claimed by notify
+}
+
+// ----------------------------------------------------------------------------
+// Future Object
+// ----------------------------------------------------------------------------
+
+// future.atomicFuture supports future.TypedFuture and future.TypedProvider
+// (and the optional future.TypedContextFuture, future.TypedSelectableFuture,
+// future.TypedCallbackFuture & future.Canceller) via an atomic state word, in
+// lieu of locks.
+//
+// Consumers park on a single done channel, shared by all waiters, which is
+// only allocated (by the first waiter) if the result is not yet available.
+// Waiters abandoned by a timed out TryGet (or GetContext) leave nothing
+// behind, so repeated retries do not accumulate. Callbacks are pushed on a
+// lock-free stack, and run (in order of registration) by whichever goroutine
+// claims the notifying flag.
+type atomicFuture[T any] struct {
+	state     atomic.Uint32                   // see state consts
+	done      atomic.Pointer[chan struct{}]   // see park - lazily created
+	cancelled atomic.Pointer[chan struct{}]   // see Cancelled - lazily created
+	callbacks atomic.Pointer[callbackNode[T]] // stack of pending callbacks
+	notifying atomic.Bool                     // callbacks are being run
+	r         TypedResult[T]                  // valid once state is set | cancelled
+	err       error                           // valid once state is cancelled
+	broadcast bool                            // see future.Broadcast
+	nilable   bool                            // nil values of T are rejected
+	consumed  atomic.Bool                     // one-shot result handed off
+}
+
+// Creates a new lock-free type-safe Future object for values of type T.
+// The options are per future.NewFuture. The returned future supports the
+// same interfaces (and contract) as that of future.NewFuture.
+func NewAtomicFuture[T any](opts ...Option) *atomicFuture[T] {
+	o := newOptions(opts)
+	return &atomicFuture[T]{
+		broadcast: o.broadcast,
+		nilable:   !o.allowNil && nilable[T](),
+	}
+}
+
+// Creates a new lock-free untyped Future object.
+func NewUntypedAtomicFuture(opts ...Option) *atomicFuture[interface{}] {
+	return NewAtomicFuture[interface{}](opts...)
+}
+
+// ______________________________________________________________________
+// support for future.Future
+
+// interface: future.Future#Get
+func (f *atomicFuture[T]) Get() (r TypedResult[T]) {
+	if !f.isDone() {
+		<-f.park()
+	}
+	return f.result("Get")
+}
+
+// interface: future.Future#TryGet
+func (f *atomicFuture[T]) TryGet(ns time.Duration) (r TypedResult[T], timeout bool) {
+	if f.isDone() {
+		return f.result("TryGet"), false
+	}
+	if ns <= 0 {
+		return nil, true
+	}
+	timer := getTimer(ns)
+	select {
+	case <-f.park():
+	case <-timer.C:
+		timeout = true
+	}
+	putTimer(timer)
+	if timeout {
+		return nil, true
+	}
+	return f.result("TryGet"), false
+}
+
+// interface: future.ContextFuture#GetContext
+func (f *atomicFuture[T]) GetContext(ctx context.Context) (r TypedResult[T], err error) {
+	if !f.isDone() {
+		select {
+		case <-f.park():
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		}
+	}
+	return f.result("GetContext"), nil
+}
+
+// interface: future.SelectableFuture#Done
+func (f *atomicFuture[T]) Done() <-chan struct{} {
+	return f.park()
+}
+
+// interface: future.SelectableFuture#Poll
+func (f *atomicFuture[T]) Poll() (r TypedResult[T], ok bool) {
+	if f.isDone() {
+		return f.result("Poll"), true
+	}
+	return nil, false
+}
+
+// interface: future.Canceller#Cancel
+func (f *atomicFuture[T]) Cancel(cause error) bool {
+	if !f.state.CompareAndSwap(statePending, stateCancelling) {
+		return false
+	}
+	f.err = &CancelledError{Cause: cause}
+	f.r = &result[T]{e: f.err, isError: true}
+	f.state.Store(stateCancelled)
+
+	if ch := f.cancelled.Swap(&closedChan); ch != nil && ch != &closedChan {
+		close(*ch)
+	}
+	f.release()
+	f.notify()
+	return true
+}
+
+// isDone returns true if the result is available.
+func (f *atomicFuture[T]) isDone() bool {
+	switch f.state.Load() {
+	case stateSet, stateCancelled:
+		return true
+	}
+	return false
+}
+
+// result returns the set result. see futureResult#result.
+func (f *atomicFuture[T]) result(op string) TypedResult[T] {
+	if !f.broadcast && f.consumed.Swap(true) {
+		return &result[T]{e: &StateError{op, ErrAlreadyConsumed}, isError: true}
+	}
+	return f.r
+}
+
+// park returns the done channel of the future, closed on completion,
+// creating it if this is the first waiter. Returns a closed channel if the
+// future is completed.
+func (f *atomicFuture[T]) park() <-chan struct{} {
+	if ch := f.done.Load(); ch != nil {
+		return *ch
+	}
+	ch := make(chan struct{})
+	if f.done.CompareAndSwap(nil, &ch) {
+		return ch
+	}
+	return *f.done.Load()
+}
+
+// release unparks all waiters. Subsequent waiters are not parked.
+func (f *atomicFuture[T]) release() {
+	if ch := f.done.Swap(&closedChan); ch != nil && ch != &closedChan {
+		close(*ch)
+	}
+}
+
+// ______________________________________________________________________
+// support for future.CallbackFuture
+
+// interface: future.CallbackFuture#OnComplete
+func (f *atomicFuture[T]) OnComplete(fn func(r TypedResult[T])) {
+	n := &callbackNode[T]{fn: fn}
+	for {
+		n.next = f.callbacks.Load()
+		if f.callbacks.CompareAndSwap(n.next, n) {
+			break
+		}
+	}
+	if f.isDone() {
+		f.notify()
+	}
+}
+
+// interface: future.CallbackFuture#OnSuccess
+func (f *atomicFuture[T]) OnSuccess(fn func(v T)) {
+	f.OnComplete(func(r TypedResult[T]) {
+		if !r.IsError() {
+			fn(r.Value())
+		}
+	})
+}
+
+// interface: future.CallbackFuture#OnFailure
+func (f *atomicFuture[T]) OnFailure(fn func(e error)) {
+	f.OnComplete(func(r TypedResult[T]) {
+		if r.IsError() {
+			fn(r.Error())
+		}
+	})
+}
+
+// notify runs the pending callbacks of a completed future, in order of
+// registration, until none remain. Only one goroutine (per f.notifying)
+// runs the callbacks at a time: a callback registered meanwhile is run by
+// the notifying goroutine, which checks for callbacks after releasing the
+// flag.
+func (f *atomicFuture[T]) notify() {
+	for f.callbacks.Load() != nil && f.notifying.CompareAndSwap(false, true) {
+		var pending *callbackNode[T] // in order of registration
+		for n := f.callbacks.Swap(nil); n != nil; {
+			next := n.next
+			n.next, pending = pending, n
+			n = next
+		}
+		for n := pending; n != nil; n = n.next {
+			callback(n.fn, f.r)
+		}
+		f.notifying.Store(false)
+	}
+}
+
+// ______________________________________________________________________
+// support for future.Provider
+
+// interface: future.Provider#SetError
+func (f *atomicFuture[T]) SetError(e error) error {
+	if e == nil {
+		return &StateError{"SetError", ErrNilValue}
+	}
+	return f.complete("SetError", &result[T]{e: e, isError: true})
+}
+
+// interface: future.Provider#SetValue
+func (f *atomicFuture[T]) SetValue(v T) error {
+	if f.nilable && isNil(v) {
+		return &StateError{"SetValue", ErrNilValue}
+	}
+	return f.complete("SetValue", &result[T]{v: v})
+}
+
+// interface: future.Provider#Cancelled
+func (f *atomicFuture[T]) Cancelled() <-chan struct{} {
+	if ch := f.cancelled.Load(); ch != nil {
+		return *ch
+	}
+	ch := make(chan struct{})
+	if f.cancelled.CompareAndSwap(nil, &ch) {
+		return ch
+	}
+	return *f.cancelled.Load()
+}
+
+// interface: future.Provider#Err
+func (f *atomicFuture[T]) Err() error {
+	if f.state.Load() == stateCancelled {
+		return f.err
+	}
+	return nil
+}
+
+// complete sets the result of the future if it is not already set.
+// Safe for concurrent use - exactly one call (of complete or Cancel) wins.
+func (f *atomicFuture[T]) complete(op string, r TypedResult[T]) error {
+	if !f.state.CompareAndSwap(statePending, stateSetting) {
+		switch f.state.Load() {
+		case stateCancelling, stateCancelled:
+			return &StateError{op, ErrCancelled}
+		}
+		return &StateError{op, ErrAlreadySet}
+	}
+	f.r = r
+	f.state.Store(stateSet)
+	f.release()
+	f.notify()
+	return nil
+}
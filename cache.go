@@ -0,0 +1,226 @@
+package future
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ----------------------------------------------------------------------------
+// Cache
+// ----------------------------------------------------------------------------
+
+// future.Loader loads the value (or error) for the key, per the provider
+// contract of p: it may set p on any goroutine, and should stop early if p is
+// cancelled. A panic in the loader is provided as a *PanicError error result.
+type Loader[K comparable, V any] func(key K, p TypedProvider[V])
+
+// future.CacheConfig configures the expiry and eviction of a future.Cache.
+// A zero CacheConfig caches values without limit, and evicts errors.
+type CacheConfig struct {
+	TTL          time.Duration // expiry of values after load - 0 for none
+	MaxEntries   int           // limit on entries, evicted per LRU - 0 for none
+	RefreshAhead time.Duration // reload of values due to expire within - 0 for none
+	NegativeTTL  time.Duration // expiry of errors - 0 to evict errors on load
+}
+
+// future.CacheStats is a snapshot of the metrics of a future.Cache.
+type CacheStats struct {
+	Hits      uint64 // calls of Get handed a cached (or loading) future
+	Misses    uint64 // calls of Get that started a load
+	Refreshes uint64 // refresh-ahead loads started
+	Evictions uint64 // entries evicted per expiry, LRU, or error
+}
+
+// cacheEntry is the cached (broadcast) future of a key.
+type cacheEntry[K comparable, V any] struct {
+	key        K
+	f          *futureResult[V]
+	loaded     bool      // f is set, and expires is valid
+	expires    time.Time // zero for none
+	refreshing bool
+}
+
+// future.Cache is a loading cache of futures. A miss starts a load of the key
+// per the cache's loader, and all callers for the key, concurrent or later,
+// share the (broadcast) future of the load until the entry is evicted.
+//
+// Values expire per the configured TTL, and may be refreshed ahead of their
+// expiry: the refreshed future replaces the cached one once set with a value,
+// and a failed refresh leaves the cached value in place until it expires.
+// Error results are cached per the configured NegativeTTL, or evicted.
+// A cached future that is cancelled (per future.Canceller) is evicted.
+//
+// A Cache is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	config  CacheConfig
+	loader  Loader[K, V]
+	mu      sync.Mutex // guards below
+	entries map[K]*list.Element
+	lru     *list.List // of *cacheEntry - most recently used at front
+	stats   CacheStats
+}
+
+// Creates a new Cache, loading missing keys per the loader.
+func NewCache[K comparable, V any](config CacheConfig, loader Loader[K, V]) *Cache[K, V] {
+	return &Cache[K, V]{
+		config:  config,
+		loader:  loader,
+		entries: make(map[K]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Creates a new Cache of untyped futures keyed by string.
+func NewUntypedCache(config CacheConfig, loader Loader[string, interface{}]) *Cache[string, interface{}] {
+	return NewCache[string, interface{}](config, loader)
+}
+
+// Returns the cached future for the key, or the future of a new load of the
+// key if none is cached (or the cached result has expired).
+func (c *Cache[K, V]) Get(key K) TypedFuture[V] {
+	now := time.Now()
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*cacheEntry[K, V])
+		if !e.loaded || e.expires.IsZero() || now.Before(e.expires) {
+			c.stats.Hits++
+			c.lru.MoveToFront(elem)
+			var refresh *futureResult[V]
+			if c.dueForRefresh(e, now) {
+				e.refreshing = true
+				c.stats.Refreshes++
+				refresh = c.newLoad(elem, true)
+			}
+			f := e.f
+			c.mu.Unlock()
+
+			if refresh != nil {
+				c.load(key, refresh)
+			}
+			return f
+		}
+		c.evict(elem) // expired
+	}
+
+	c.stats.Misses++
+	elem := c.lru.PushFront(&cacheEntry[K, V]{key: key})
+	c.entries[key] = elem
+	f := c.newLoad(elem, false)
+	elem.Value.(*cacheEntry[K, V]).f = f
+	for c.config.MaxEntries > 0 && c.lru.Len() > c.config.MaxEntries {
+		c.evict(c.lru.Back())
+	}
+	c.mu.Unlock()
+
+	c.load(key, f)
+	return f
+}
+
+// Evicts the entry (if any) of the key. Callers of its future are unaffected.
+func (c *Cache[K, V]) Invalidate(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.evict(elem)
+	}
+}
+
+// Returns the number of cached entries, including those loading or expired
+// (but not yet evicted).
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// Returns a snapshot of the metrics of the cache.
+func (c *Cache[K, V]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// dueForRefresh returns true if the loaded value of e is due to expire
+// within the refresh-ahead duration. caller must hold c.mu.
+func (c *Cache[K, V]) dueForRefresh(e *cacheEntry[K, V], now time.Time) bool {
+	if c.config.RefreshAhead <= 0 || !e.loaded || e.refreshing || e.expires.IsZero() {
+		return false
+	}
+	if r, ok := e.f.Poll(); !ok || r.IsError() {
+		return false
+	}
+	return !now.Before(e.expires.Add(-c.config.RefreshAhead))
+}
+
+// newLoad returns a new (broadcast) future for a load of the entry elem,
+// whose completion updates the entry. caller must hold c.mu.
+func (c *Cache[K, V]) newLoad(elem *list.Element, refresh bool) *futureResult[V] {
+	f := NewFuture[V](Broadcast())
+	f.OnComplete(func(r TypedResult[V]) { c.loaded(elem, f, r, refresh) })
+	return f
+}
+
+// load runs the loader for the key, setting f, on a new goroutine.
+func (c *Cache[K, V]) load(key K, f *futureResult[V]) {
+	go func() {
+		_, e := try(func() (struct{}, error) {
+			c.loader(key, f)
+			return struct{}{}, nil
+		})
+		if e != nil {
+			f.SetError(e)
+		}
+	}()
+}
+
+// loaded updates the entry elem (unless since evicted) per the result r of
+// its load (or refresh) f.
+func (c *Cache[K, V]) loaded(elem *list.Element, f *futureResult[V], r TypedResult[V], refresh bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := elem.Value.(*cacheEntry[K, V])
+	if c.entries[e.key] != elem {
+		return // evicted
+	}
+	if refresh {
+		e.refreshing = false
+		if r.IsError() {
+			return // keep the cached value until it expires
+		}
+		e.f = f
+	}
+
+	switch {
+	case f.Err() != nil:
+		c.evict(elem) // cancelled
+	case !r.IsError():
+		e.loaded = true
+		e.expires = c.expiry(c.config.TTL)
+	case c.config.NegativeTTL > 0:
+		e.loaded = true
+		e.expires = c.expiry(c.config.NegativeTTL)
+	default:
+		c.evict(elem)
+	}
+}
+
+// expiry returns the expiry time for the ttl - zero for none.
+func (c *Cache[K, V]) expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// evict removes the entry elem. caller must hold c.mu.
+func (c *Cache[K, V]) evict(elem *list.Element) {
+	delete(c.entries, elem.Value.(*cacheEntry[K, V]).key)
+	c.lru.Remove(elem)
+	c.stats.Evictions++
+}
@@ -0,0 +1,187 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingLoader returns a loader that provides the key's load count (per
+// the loads counter) as its value, after an (optional) delay, or err if
+// non-nil.
+func countingLoader(delay time.Duration, err *atomic.Value) (Loader[string, int], *int32) {
+	var loads int32
+	return func(key string, p TypedProvider[int]) {
+		n := atomic.AddInt32(&loads, 1)
+		time.Sleep(delay)
+		if e, ok := err.Load().(error); ok && e != nil {
+			p.SetError(e)
+			return
+		}
+		p.SetValue(int(n))
+	}, &loads
+}
+
+// concurrent misses for a key
+// MUST share a single load, with the value cached thereafter
+func TestCacheSharedLoad(t *testing.T) {
+	var err atomic.Value
+	loader, loads := countingLoader(10*time.Millisecond, &err)
+	c := NewCache(CacheConfig{}, loader)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if result, timeout := c.Get("k").TryGet(time.Second); timeout || result.Value() != 1 {
+				t.Errorf("unexpected result %v", result)
+			}
+		}()
+	}
+	wg.Wait()
+	if result := c.Get("k").Get(); result.Value() != 1 || atomic.LoadInt32(loads) != 1 {
+		t.Errorf("expected 1 load - got %d", atomic.LoadInt32(loads))
+	}
+	if stats := c.Stats(); stats.Misses != 1 || stats.Hits != 10 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// values past their TTL
+// MUST be reloaded
+func TestCacheTTL(t *testing.T) {
+	test := testSpec()
+	var err atomic.Value
+	loader, _ := countingLoader(0, &err)
+	c := NewCache(CacheConfig{TTL: test.wait}, loader)
+
+	if result := c.Get("k").Get(); result.Value() != 1 {
+		t.Fatalf("unexpected result %v", result.Value())
+	}
+	waitFor(t, "load", func() bool { return c.Get("k").Get().Value() == 1 })
+	time.Sleep(2 * test.wait)
+	if result := c.Get("k").Get(); result.Value() != 2 {
+		t.Errorf("expected reload after TTL - got %v", result.Value())
+	}
+}
+
+// entries in excess of MaxEntries
+// MUST be evicted in LRU order
+func TestCacheLRU(t *testing.T) {
+	var err atomic.Value
+	loader, loads := countingLoader(0, &err)
+	c := NewCache(CacheConfig{MaxEntries: 2}, loader)
+
+	c.Get("a").Get()
+	c.Get("b").Get()
+	c.Get("a").Get() // b is now least recently used
+	c.Get("c").Get() // evicts b
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 entries - got %d", c.Len())
+	}
+	n := atomic.LoadInt32(loads)
+	c.Get("a").Get()
+	if atomic.LoadInt32(loads) != n {
+		t.Error("expected a cached")
+	}
+	c.Get("b").Get()
+	if atomic.LoadInt32(loads) != n+1 {
+		t.Error("expected b evicted and reloaded")
+	}
+}
+
+// error results
+// MUST be evicted, or cached per NegativeTTL
+func TestCacheErrors(t *testing.T) {
+	test := testSpec()
+	var err atomic.Value
+	err.Store(test.err)
+
+	loader, loads := countingLoader(0, &err)
+	c := NewCache(CacheConfig{}, loader)
+	if result := c.Get("k").Get(); result.Error() != test.err {
+		t.Fatalf("expected spec error - got %v", result.Error())
+	}
+	waitFor(t, "eviction", func() bool { return c.Len() == 0 })
+	c.Get("k").Get()
+	if atomic.LoadInt32(loads) != 2 {
+		t.Errorf("expected error evicted - got %d loads", atomic.LoadInt32(loads))
+	}
+
+	loader, loads = countingLoader(0, &err)
+	c = NewCache(CacheConfig{NegativeTTL: time.Hour}, loader)
+	c.Get("k").Get()
+	waitFor(t, "load", func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.entries["k"].Value.(*cacheEntry[string, int]).loaded
+	})
+	if result := c.Get("k").Get(); result.Error() != test.err || atomic.LoadInt32(loads) != 1 {
+		t.Errorf("expected error cached - got %d loads", atomic.LoadInt32(loads))
+	}
+}
+
+// values due to expire
+// MUST be refreshed ahead, swapping in the new value
+func TestCacheRefreshAhead(t *testing.T) {
+	var err atomic.Value
+	loader, loads := countingLoader(0, &err)
+	c := NewCache(CacheConfig{TTL: 50 * time.Millisecond, RefreshAhead: 40 * time.Millisecond}, loader)
+
+	if result := c.Get("k").Get(); result.Value() != 1 {
+		t.Fatalf("unexpected result %v", result.Value())
+	}
+	time.Sleep(20 * time.Millisecond) // within refresh-ahead window
+	if result := c.Get("k").Get(); result.Value() != 1 {
+		t.Errorf("expected cached value during refresh - got %v", result.Value())
+	}
+	waitFor(t, "refresh", func() bool { return c.Get("k").Get().Value() == 2 })
+	if stats := c.Stats(); stats.Refreshes < 1 || stats.Misses != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if atomic.LoadInt32(loads) < 2 {
+		t.Error("expected refresh load")
+	}
+}
+
+// cached future cancelled, or invalidated
+// MUST be evicted
+func TestCacheCancelInvalidate(t *testing.T) {
+	c := NewUntypedCache(CacheConfig{}, func(key string, p Provider) {
+		<-p.Cancelled()
+	})
+
+	f := c.Get("k")
+	f.(Canceller).Cancel(nil)
+	if result := f.Get(); !errors.Is(result.Error(), ErrCancelled) {
+		t.Fatalf("expected ErrCancelled - got %v", result.Error())
+	}
+	waitFor(t, "eviction", func() bool { return c.Len() == 0 })
+
+	g := c.Get("k")
+	c.Invalidate("k")
+	if c.Get("k") == g {
+		t.Error("expected new load after Invalidate")
+	}
+	for _, f := range []Future{g, c.Get("k")} {
+		f.(Canceller).Cancel(nil)
+	}
+}
+
+// panicking loader
+// MUST provide a PanicError error result
+func TestCacheLoaderPanic(t *testing.T) {
+	test := testSpec()
+	c := NewUntypedCache(CacheConfig{}, func(key string, p Provider) {
+		panic(test.data)
+	})
+	var pe *PanicError
+	if result := c.Get("k").Get(); !errors.As(result.Error(), &pe) {
+		t.Errorf("expected PanicError - got %v", result.Error())
+	}
+}
@@ -0,0 +1,172 @@
+package future
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+/* Combinators of sets of futures - each returns a new future */
+
+// interval of TryGet polls of futures that are not future.TypedCallbackFuture
+const pollInterval = 10 * time.Millisecond
+
+// ----------------------------------------------------------------------------
+// Combinators
+// ----------------------------------------------------------------------------
+
+// future.All returns a future for the results of all futures, in order. It
+// fails fast: the first error result of futures is the error result of the
+// returned future.
+//
+// An (optional) overall wait duration may be specified, per TryGet, after
+// which the returned future completes with a *future.TimeoutError.
+//
+// The given futures must not be consumed elsewhere, unless they are
+// future.TypedCallbackFuture (or broadcast) futures.
+func All[T any](futures []TypedFuture[T], wait ...time.Duration) TypedFuture[[]TypedResult[T]] {
+	g := NewFuture[[]TypedResult[T]]()
+	var mu sync.Mutex
+	results := make([]TypedResult[T], len(futures))
+	pending := len(futures)
+	if pending == 0 {
+		g.SetValue(results)
+		return g
+	}
+	for i, f := range futures {
+		i := i
+		watch(f, g.Done(), func(r TypedResult[T]) {
+			if r.IsError() {
+				g.SetError(r.Error())
+				return
+			}
+			mu.Lock()
+			results[i] = r
+			pending--
+			done := pending == 0
+			mu.Unlock()
+			if done {
+				g.SetValue(results)
+			}
+		})
+	}
+	timeout(g, wait)
+	return g
+}
+
+// future.AllSettled returns a future for the results, value or error, of all
+// futures, in order. The returned future only results in an error if the
+// (optional) wait duration elapses. See future.All.
+func AllSettled[T any](futures []TypedFuture[T], wait ...time.Duration) TypedFuture[[]TypedResult[T]] {
+	g := NewFuture[[]TypedResult[T]]()
+	var mu sync.Mutex
+	results := make([]TypedResult[T], len(futures))
+	pending := len(futures)
+	if pending == 0 {
+		g.SetValue(results)
+		return g
+	}
+	for i, f := range futures {
+		i := i
+		watch(f, g.Done(), func(r TypedResult[T]) {
+			mu.Lock()
+			results[i] = r
+			pending--
+			done := pending == 0
+			mu.Unlock()
+			if done {
+				g.SetValue(results)
+			}
+		})
+	}
+	timeout(g, wait)
+	return g
+}
+
+// future.Any returns a future for the first value result of futures. If all
+// futures result in errors, the returned future results in an error joining
+// (per errors.Join) all of the errors, in order. See future.All.
+func Any[T any](futures []TypedFuture[T], wait ...time.Duration) TypedFuture[T] {
+	g := NewFuture[T]()
+	var mu sync.Mutex
+	errs := make([]error, len(futures))
+	pending := len(futures)
+	if pending == 0 {
+		g.SetError(&StateError{"Any", ErrNoFutures})
+		return g
+	}
+	for i, f := range futures {
+		i := i
+		watch(f, g.Done(), func(r TypedResult[T]) {
+			if !r.IsError() {
+				provide[T](g)(r.Value(), nil)
+				return
+			}
+			mu.Lock()
+			errs[i] = r.Error()
+			pending--
+			done := pending == 0
+			mu.Unlock()
+			if done {
+				g.SetError(errors.Join(errs...))
+			}
+		})
+	}
+	timeout(g, wait)
+	return g
+}
+
+// future.Race returns a future for the first result, value or error, of
+// futures. See future.All.
+func Race[T any](futures []TypedFuture[T], wait ...time.Duration) TypedFuture[T] {
+	g := NewFuture[T]()
+	if len(futures) == 0 {
+		g.SetError(&StateError{"Race", ErrNoFutures})
+		return g
+	}
+	for _, f := range futures {
+		watch(f, g.Done(), func(r TypedResult[T]) {
+			provide[T](g)(r.Value(), r.Error())
+		})
+	}
+	timeout(g, wait)
+	return g
+}
+
+// ----------------------------------------------------------------------------
+// support
+// ----------------------------------------------------------------------------
+
+// watch calls fn with the result of future f once it is available, unless
+// stop is closed first. No goroutine is used for future.TypedCallbackFuture
+// futures. Otherwise, a goroutine polls f via TryGet until f completes or
+// stop is closed, so that it is not leaked if f never completes.
+func watch[T any](f TypedFuture[T], stop <-chan struct{}, fn func(r TypedResult[T])) {
+	if cf, ok := f.(TypedCallbackFuture[T]); ok {
+		cf.OnComplete(fn)
+		return
+	}
+	go func() {
+		for {
+			if r, timeout := f.TryGet(pollInterval); !timeout {
+				fn(r)
+				return
+			}
+			select {
+			case <-stop:
+				return
+			default:
+			}
+		}
+	}()
+}
+
+// timeout completes g with a *TimeoutError after the (optional) wait
+// duration, unless g completes first.
+func timeout[T any](g *futureResult[T], wait []time.Duration) {
+	if len(wait) == 0 || wait[0] <= 0 {
+		return
+	}
+	timer := time.AfterFunc(wait[0], func() { g.SetError(&TimeoutError{wait[0]}) })
+	g.OnComplete(func(TypedResult[T]) { timer.Stop() })
+}
@@ -0,0 +1,168 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// test futures of ints 0..n-1 set in reverse order, with fails
+// indicating which are set with the spec error
+func testFutures(test testspec, n int, fails ...int) ([]TypedFuture[int], func()) {
+	futures := make([]TypedFuture[int], n)
+	objs := make([]*futureResult[int], n)
+	for i := range futures {
+		objs[i] = NewFuture[int]()
+		futures[i] = objs[i]
+	}
+	set := func() {
+	next:
+		for i := n - 1; i >= 0; i-- {
+			for _, f := range fails {
+				if f == i {
+					objs[i].SetError(test.err)
+					continue next
+				}
+			}
+			objs[i].SetValue(i)
+		}
+	}
+	return futures, set
+}
+
+// ____________________________________________________________________
+// combinators
+
+// All of value results
+// MUST result in all results, in order
+func TestAll(t *testing.T) {
+	test := testSpec()
+
+	futures, set := testFutures(test, 4)
+	g := All(futures)
+	set()
+
+	result, timeout := g.TryGet(time.Second)
+	switch {
+	case timeout:
+		t.Fatal("expected result by now")
+	case result.IsError():
+		t.Fatalf("unexpected error %s", result.Error())
+	}
+	for i, r := range result.Value() {
+		if r.Value() != i {
+			t.Errorf("%d: unexpected value %d", i, r.Value())
+		}
+	}
+}
+
+// All with an error result
+// MUST fail fast with the error
+func TestAllFailFast(t *testing.T) {
+	test := testSpec()
+
+	futures, _ := testFutures(test, 4)
+	g := All(futures)
+	futures[2].(TypedProvider[int]).SetError(test.err)
+
+	if result, timeout := g.TryGet(time.Second); timeout || result.Error() != test.err {
+		t.Fatal("expected spec error result")
+	}
+}
+
+// AllSettled with error results
+// MUST result in all results, in order
+func TestAllSettled(t *testing.T) {
+	test := testSpec()
+
+	futures, set := testFutures(test, 4, 1, 3)
+	g := AllSettled(futures)
+	set()
+
+	results := g.Get().Value()
+	for i, r := range results {
+		switch i % 2 {
+		case 0:
+			if r.Value() != i {
+				t.Errorf("%d: unexpected value %d", i, r.Value())
+			}
+		default:
+			if r.Error() != test.err {
+				t.Errorf("%d: expected spec error", i)
+			}
+		}
+	}
+}
+
+// Any with some error results
+// MUST result in the first value result
+// Any with all error results
+// MUST result in the joined errors
+func TestAny(t *testing.T) {
+	test := testSpec()
+
+	futures, set := testFutures(test, 4, 3, 2)
+	g := Any(futures)
+	set()
+	if result := g.Get(); result.IsError() || result.Value() != 1 {
+		t.Errorf("expected first value result 1 - got %v", result.Value())
+	}
+
+	futures, set = testFutures(test, 2, 0, 1)
+	g = Any(futures)
+	set()
+	if result := g.Get(); !errors.Is(result.Error(), test.err) {
+		t.Errorf("expected joined spec errors - got %v", result.Error())
+	}
+}
+
+// Race
+// MUST result in the first result, value or error
+func TestRace(t *testing.T) {
+	test := testSpec()
+
+	futures, set := testFutures(test, 4, 3)
+	g := Race(futures)
+	set()
+	if result := g.Get(); result.Error() != test.err {
+		t.Errorf("expected first (error) result - got %v", result)
+	}
+}
+
+// combinators with a wait duration and futures that are never set
+// MUST result in ErrTimeout
+// MUST NOT leak polling goroutines for non-callback futures
+func TestCombinatorsTimeout(t *testing.T) {
+	test := testSpec()
+
+	goroutines := runtime.NumGoroutine()
+
+	futures, _ := testFutures(test, 4)
+	foreign := make([]TypedFuture[int], len(futures))
+	for i, f := range futures {
+		foreign[i] = struct{ TypedFuture[int] }{f}
+	}
+
+	for name, g := range map[string]TypedFuture[int]{
+		"Any":  Any(foreign, test.wait),
+		"Race": Race(futures, test.wait),
+	} {
+		if result := g.Get(); !errors.Is(result.Error(), ErrTimeout) {
+			t.Errorf("%s: expected ErrTimeout - got %v", name, result.Error())
+		}
+	}
+	if result := All(foreign, test.wait).Get(); !errors.Is(result.Error(), ErrTimeout) {
+		t.Errorf("All: expected ErrTimeout - got %v", result.Error())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(pollInterval)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("expected %d goroutines - got %d", goroutines, n)
+	}
+}
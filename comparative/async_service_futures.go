@@ -6,8 +6,8 @@ import (
 	"time"
 )
 
-// using UntypedFuture -- request is type-safe;
-// response is interface{}
+// using future.AsyncService -- both requests and
+// responses are type-safe (i.e. no interface{}).
 func main() {
 
 	server = StartServer()
@@ -16,12 +16,7 @@ func main() {
 	<-(make(chan int, 1))
 }
 
-type Server chan<- *request
-type request struct {
-	cid    int
-	arg    time.Time
-	future future.Future
-}
+type Server = *future.AsyncService[time.Time, time.Duration]
 
 var server Server
 
@@ -41,14 +36,12 @@ func startClients() {
 			var timeout bool
 
 			for true {
-				future := future.NewUntypedFuture()
-				request := &request{cid, time.Now(), future}
-				server <- request
+				response := server.Call(time.Now())
 
 				// note: typically get would occur elsewhere and not immediately after request
-				_, timeout = future.Result().TryGet(time.Duration(_WAIT))
+				_, timeout = response.TryGet(_WAIT)
 				if timeout {
-					future.Result().Get()
+					response.Get()
 				}
 
 				// client 0 will dump its results as a sample
@@ -73,24 +66,13 @@ func startClients() {
 	}
 }
 
-func StartServer() chan<- *request {
-	c := make(chan *request)
-	go func() {
-		for {
-			// process request queue or sleep if none pending
-			select {
-			case request := <-c:
-				// sleep for fake service latency
-				time.Sleep(time.Duration(_SERVICE_LATENCY) * time.Nanosecond)
+func StartServer() Server {
+	// a single worker, with an unbuffered request queue
+	return future.NewAsyncService(1, 0, future.Block, func(t0 time.Time) (time.Duration, error) {
+		// sleep for fake service latency
+		time.Sleep(time.Duration(_SERVICE_LATENCY) * time.Nanosecond)
 
-				// result is just the delta of t0 of request and time now
-				t0 := request.arg
-				delta := time.Now().Sub(t0)
-				request.future.SetValue(delta)
-			default:
-				time.Sleep(1 * time.Nanosecond)
-			}
-		}
-	}()
-	return c
+		// result is just the delta of t0 of request and time now
+		return time.Now().Sub(t0), nil
+	})
 }
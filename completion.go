@@ -0,0 +1,160 @@
+package future
+
+import (
+	"sync"
+	"time"
+)
+
+// ----------------------------------------------------------------------------
+// Completion Service
+// ----------------------------------------------------------------------------
+
+// completion is a completed future and its result.
+type completion[T any] struct {
+	f TypedFuture[T]
+	r TypedResult[T]
+}
+
+// future.CompletionService consumes a set of futures in the order of
+// their completion, rather than in the order they were added.
+//
+// Futures are added directly (per Add), or as the futures of submitted
+// tasks (per Submit). Each added future is consumed (exactly once) by the
+// service, and its result is handed off (with the future) by Take | Poll |
+// PollTimeout.
+//
+// A CompletionService is safe for concurrent use.
+type CompletionService[T any] struct {
+	executor    *Executor     // nil - tasks are run per future.Async
+	ready       chan struct{} // signals a non-empty completed queue
+	mu          sync.Mutex    // guards completed and outstanding
+	completed   []completion[T]
+	outstanding int
+}
+
+// Creates a new CompletionService. Submitted tasks are run by the (optional)
+// executor, or per future.Async given a nil executor.
+func NewCompletionService[T any](executor *Executor) *CompletionService[T] {
+	return &CompletionService[T]{
+		executor: executor,
+		ready:    make(chan struct{}, 1),
+	}
+}
+
+// Creates a new CompletionService of untyped futures. See
+// future.NewCompletionService.
+func NewUntypedCompletionService(executor *Executor) *CompletionService[interface{}] {
+	return NewCompletionService[interface{}](executor)
+}
+
+// Adds the future to the service. Add of a nil future is a no-op.
+func (s *CompletionService[T]) Add(f TypedFuture[T]) {
+	if f == nil {
+		return
+	}
+	s.mu.Lock()
+	s.outstanding++
+	s.mu.Unlock()
+
+	whenComplete(f, func(r TypedResult[T]) {
+		s.mu.Lock()
+		s.completed = append(s.completed, completion[T]{f, r})
+		s.mu.Unlock()
+		s.signal()
+	})
+}
+
+// Submits the task for execution, and adds the future of its result to the
+// service. Returns the added future.
+func (s *CompletionService[T]) Submit(task func() (T, error)) TypedFuture[T] {
+	var f TypedFuture[T]
+	if s.executor != nil {
+		f = Execute(s.executor, task)
+	} else {
+		f = Async(task)
+	}
+	s.Add(f)
+	return f
+}
+
+// Returns the next completed future, and its result, waiting for one to
+// complete if none has. Returns nil (and nil) if no futures are outstanding.
+func (s *CompletionService[T]) Take() (TypedFuture[T], TypedResult[T]) {
+	for {
+		if f, r, ok := s.poll(); ok || f != nil {
+			return f, r
+		}
+		<-s.ready
+	}
+}
+
+// Returns the next completed future, and its result, or nil (and nil) if
+// none has completed.
+func (s *CompletionService[T]) Poll() (TypedFuture[T], TypedResult[T]) {
+	f, r, _ := s.poll()
+	return f, r
+}
+
+// Returns the next completed future, and its result, waiting at most wait
+// for one to complete. Returns nil (and nil) if none completed in time, or if
+// no futures are outstanding.
+func (s *CompletionService[T]) PollTimeout(wait time.Duration) (TypedFuture[T], TypedResult[T]) {
+	var timer *time.Timer
+	for {
+		if f, r, ok := s.poll(); ok || f != nil {
+			if timer != nil {
+				putTimer(timer)
+			}
+			return f, r
+		}
+		if timer == nil {
+			timer = getTimer(wait)
+		}
+		select {
+		case <-s.ready:
+		case <-timer.C:
+			putTimer(timer)
+			return nil, nil
+		}
+	}
+}
+
+// Returns the number of outstanding futures: added, and not yet handed off
+// by Take | Poll | PollTimeout.
+func (s *CompletionService[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.outstanding
+}
+
+// poll hands off the next completed future, if any. The returned flag is
+// true if no futures are outstanding (and there is nothing to wait for).
+func (s *CompletionService[T]) poll() (TypedFuture[T], TypedResult[T], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.completed) == 0 {
+		if s.outstanding > 0 {
+			return nil, nil, false
+		}
+		s.signal() // cascade the wake up to all waiting consumers
+		return nil, nil, true
+	}
+	c := s.completed[0]
+	s.completed[0] = completion[T]{}
+	s.completed = s.completed[1:]
+	s.outstanding--
+	if len(s.completed) > 0 || s.outstanding == 0 {
+		s.signal() // wake the next consumer - to take, or to return
+	}
+	return c.f, c.r, false
+}
+
+// signal wakes (at most) one waiting consumer.
+func (s *CompletionService[T]) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,138 @@
+/* white box tests */
+
+package future
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// futures completing in reverse order of addition
+// MUST be taken in order of completion
+func TestCompletionServiceOrder(t *testing.T) {
+	s := NewCompletionService[int](nil)
+
+	const n = 5
+	providers := make([]*futureResult[int], n)
+	for i := range providers {
+		providers[i] = NewFuture[int]()
+		s.Add(providers[i])
+	}
+	if s.Len() != n {
+		t.Fatalf("expected %d outstanding - got %d", n, s.Len())
+	}
+	if f, r := s.Poll(); f != nil || r != nil {
+		t.Fatal("expected Poll to return nil before completion")
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		providers[i].SetValue(i)
+		f, r := s.Take()
+		if f != TypedFuture[int](providers[i]) || r.Value() != i {
+			t.Fatalf("expected future %d - got %v", i, r)
+		}
+	}
+	if s.Len() != 0 {
+		t.Errorf("expected none outstanding - got %d", s.Len())
+	}
+	if f, r := s.Take(); f != nil || r != nil {
+		t.Error("expected Take to return nil with none outstanding")
+	}
+}
+
+// submitted tasks (via executor, or async)
+// MUST all be taken, with their results
+func TestCompletionServiceSubmit(t *testing.T) {
+	test := testSpec()
+	e := NewExecutor(2, 8, Block)
+	defer e.Shutdown()
+
+	for _, s := range []*CompletionService[interface{}]{NewUntypedCompletionService(e), NewUntypedCompletionService(nil)} {
+		s.Submit(func() (interface{}, error) { return nil, test.err })
+		for i := 0; i < 4; i++ {
+			d := time.Duration(i) * time.Millisecond
+			s.Submit(func() (interface{}, error) {
+				time.Sleep(d)
+				return d, nil
+			})
+		}
+
+		var values, errs int
+		for s.Len() > 0 {
+			_, r := s.Take()
+			if r.IsError() {
+				if r.Error() != test.err {
+					t.Errorf("expected spec error - got %v", r.Error())
+				}
+				errs++
+			} else {
+				values++
+			}
+		}
+		if values != 4 || errs != 1 {
+			t.Errorf("expected 4 values & 1 error - got %d & %d", values, errs)
+		}
+	}
+}
+
+// PollTimeout
+// MUST return nil on timeout, and the completed future otherwise
+func TestCompletionServicePollTimeout(t *testing.T) {
+	test := testSpec()
+	s := NewUntypedCompletionService(nil)
+
+	f := NewUntypedFuture()
+	s.Add(f)
+	if g, r := s.PollTimeout(test.wait); g != nil || r != nil {
+		t.Fatal("expected PollTimeout to time out")
+	}
+
+	time.AfterFunc(test.wait, func() { f.SetValue(test.data) })
+	g, r := s.PollTimeout(time.Second)
+	if g != Future(f) || r == nil || r.IsError() {
+		t.Fatalf("expected completed future - got %v", r)
+	}
+	if g, r := s.PollTimeout(time.Hour); g != nil || r != nil {
+		t.Error("expected PollTimeout to return nil with none outstanding")
+	}
+}
+
+// concurrent consumers taking from one service
+// MUST each future exactly once, and all return once drained
+// note: run with -race
+func TestCompletionServiceConcurrent(t *testing.T) {
+	s := NewCompletionService[int](nil)
+
+	const n = 200
+	for i := 0; i < n; i++ {
+		i := i
+		s.Submit(func() (int, error) { return i, nil })
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	var wg sync.WaitGroup
+	for c := 0; c < 4; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				f, r := s.Take()
+				if f == nil {
+					return
+				}
+				mu.Lock()
+				if seen[r.Value()] {
+					t.Errorf("%d taken twice", r.Value())
+				}
+				seen[r.Value()] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != n {
+		t.Errorf("expected %d taken - got %d", n, len(seen))
+	}
+}
@@ -0,0 +1,298 @@
+/* white box tests */
+
+package future
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+/// contract test spec /////////////////////////////////////////////////
+
+// the type-safe future objects (of each implementation) under contract test
+type typedContractFuture[T any] interface {
+	TypedContextFuture[T]
+	TypedSelectableFuture[T]
+	TypedCallbackFuture[T]
+	TypedProvider[T]
+	Canceller
+}
+
+// the untyped future objects under contract test
+type contractFuture = typedContractFuture[interface{}]
+
+// an implementation under contract test
+type contractImpl[T any] struct {
+	name      string
+	newFuture func(opts ...Option) typedContractFuture[T]
+}
+
+// the implementations under contract test, for values of type T
+func typedContractImpls[T any]() []contractImpl[T] {
+	return []contractImpl[T]{
+		{"channel", func(opts ...Option) typedContractFuture[T] { return NewFuture[T](opts...) }},
+		{"atomic", func(opts ...Option) typedContractFuture[T] { return NewAtomicFuture[T](opts...) }},
+	}
+}
+
+// the untyped implementations under contract test
+var contractImpls = typedContractImpls[interface{}]()
+
+// runs the contract test fn for each implementation
+func testContract(t *testing.T, fn func(t *testing.T, newFuture func(opts ...Option) contractFuture)) {
+	testTypedContract(t, fn)
+}
+
+// runs the type-safe contract test fn for each implementation
+func testTypedContract[T any](t *testing.T, fn func(t *testing.T, newFuture func(opts ...Option) typedContractFuture[T])) {
+	for _, impl := range typedContractImpls[T]() {
+		t.Run(impl.name, func(t *testing.T) { fn(t, impl.newFuture) })
+	}
+}
+
+/// contract tests /////////////////////////////////////////////////////
+
+// TryGet of a future not set
+// MUST timeout with nil result
+// Get after set (following TryGet timeouts)
+// MUST return the value result
+func TestContractTryGetThenGet(t *testing.T) {
+	testContract(t, func(t *testing.T, newFuture func(...Option) contractFuture) {
+		test := testSpec()
+
+		f := newFuture()
+		for i := 0; i < 3; i++ {
+			if result, timeout := f.TryGet(test.providerDelay); !timeout || result != nil {
+				t.Fatal("expected timeout with nil result")
+			}
+		}
+		go func() {
+			time.Sleep(test.providerDelay)
+			f.SetValue(test.data)
+		}()
+		if result := f.Get(); result.IsError() || result.Value() == nil {
+			t.Error("expected value result")
+		}
+	})
+}
+
+// SetError then TryGet | GetContext
+// MUST return the error result
+func TestContractSetError(t *testing.T) {
+	testContract(t, func(t *testing.T, newFuture func(...Option) contractFuture) {
+		test := testSpec()
+
+		f := newFuture(Broadcast())
+		f.SetError(test.err)
+		if result, timeout := f.TryGet(0); timeout || result.Error() != test.err {
+			t.Error("expected spec error result for TryGet")
+		}
+		if result, e := f.GetContext(context.Background()); e != nil || result.Error() != test.err {
+			t.Error("expected spec error result for GetContext")
+		}
+	})
+}
+
+// GetContext of a future not set with done context
+// MUST return the context error
+func TestContractGetContext(t *testing.T) {
+	testContract(t, func(t *testing.T, newFuture func(...Option) contractFuture) {
+		test := testSpec()
+
+		f := newFuture()
+		ctx, cancel := context.WithTimeout(context.Background(), test.providerDelay)
+		defer cancel()
+		if _, e := f.GetContext(ctx); e != context.DeadlineExceeded {
+			t.Errorf("expected context.DeadlineExceeded - got %v", e)
+		}
+	})
+}
+
+// sets of a set future, and nil sets
+// MUST return ErrAlreadySet | ErrNilValue
+// one-shot repeated Get
+// MUST return ErrAlreadyConsumed
+func TestContractStateErrors(t *testing.T) {
+	testContract(t, func(t *testing.T, newFuture func(...Option) contractFuture) {
+		test := testSpec()
+
+		f := newFuture()
+		if e := f.SetValue(nil); !errors.Is(e, ErrNilValue) {
+			t.Errorf("expected ErrNilValue - got %v", e)
+		}
+		if e := f.SetError(nil); !errors.Is(e, ErrNilValue) {
+			t.Errorf("expected ErrNilValue - got %v", e)
+		}
+		f.SetValue(test.data)
+		if e := f.SetError(test.err); !errors.Is(e, ErrAlreadySet) {
+			t.Errorf("expected ErrAlreadySet - got %v", e)
+		}
+		f.Get()
+		if result := f.Get(); !errors.Is(result.Error(), ErrAlreadyConsumed) {
+			t.Errorf("expected ErrAlreadyConsumed - got %v", result.Error())
+		}
+	})
+}
+
+// Cancel of a future not set
+// MUST signal the provider and complete with ErrCancelled
+func TestContractCancel(t *testing.T) {
+	testContract(t, func(t *testing.T, newFuture func(...Option) contractFuture) {
+		test := testSpec()
+
+		f := newFuture()
+		cancelled := f.Cancelled()
+		rch := make(chan Result, 1)
+		go func() { rch <- f.Get() }()
+
+		if !f.Cancel(test.err) || f.Cancel(nil) {
+			t.Fatal("expected exactly one successful Cancel")
+		}
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("expected Cancelled() to be closed")
+		}
+		if !errors.Is(f.Err(), ErrCancelled) || !errors.Is(f.Err(), test.err) {
+			t.Errorf("unexpected Err() %v", f.Err())
+		}
+		if e := f.SetValue(test.data); !errors.Is(e, ErrCancelled) {
+			t.Errorf("expected ErrCancelled - got %v", e)
+		}
+		if result := <-rch; !errors.Is(result.Error(), ErrCancelled) {
+			t.Errorf("expected ErrCancelled result - got %v", result.Error())
+		}
+	})
+}
+
+// concurrent sets & cancels with concurrent broadcast consumers
+// MUST result in exactly one winner
+// MUST return the same result to all consumers
+// note: run with -race
+func TestContractConcurrentStress(t *testing.T) {
+	testContract(t, func(t *testing.T, newFuture func(...Option) contractFuture) {
+		test := testSpec()
+
+		for i := 0; i < 200; i++ {
+			f := newFuture(Broadcast())
+
+			var wg sync.WaitGroup
+			results := make(chan Result, 4)
+			for n := 0; n < 4; n++ {
+				wg.Add(1)
+				go func(n int) {
+					defer wg.Done()
+					if n%2 == 0 {
+						results <- f.Get()
+						return
+					}
+					for {
+						if r, timeout := f.TryGet(time.Microsecond); !timeout {
+							results <- r
+							return
+						}
+					}
+				}(n)
+			}
+
+			var winners sync.WaitGroup
+			var won int32
+			var mu sync.Mutex
+			for n := 0; n < 6; n++ {
+				winners.Add(1)
+				go func(n int) {
+					defer winners.Done()
+					var ok bool
+					switch n % 3 {
+					case 0:
+						ok = f.SetValue(test.data) == nil
+					case 1:
+						ok = f.SetError(test.err) == nil
+					default:
+						ok = f.Cancel(nil)
+					}
+					if ok {
+						mu.Lock()
+						won++
+						mu.Unlock()
+					}
+				}(n)
+			}
+			winners.Wait()
+			wg.Wait()
+			close(results)
+
+			if won != 1 {
+				t.Fatalf("round %d: expected exactly 1 winner - got %d", i, won)
+			}
+			var first Result
+			for r := range results {
+				if first == nil {
+					first = r
+				}
+				if r == nil || r != first {
+					t.Fatalf("round %d: expected the same result for all consumers", i)
+				}
+			}
+		}
+	})
+}
+
+/// contract benchmarks ////////////////////////////////////////////////
+
+// set then get by a single consumer
+func BenchmarkContractSetGet(b *testing.B) {
+	test := testSpec()
+	for _, impl := range contractImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				f := impl.newFuture()
+				f.SetValue(test.data)
+				f.Get()
+			}
+		})
+	}
+}
+
+// hand-off to a single consumer parked in Get
+func BenchmarkContractHandOff(b *testing.B) {
+	test := testSpec()
+	for _, impl := range contractImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				f := impl.newFuture()
+				go f.SetValue(test.data)
+				f.Get()
+			}
+		})
+	}
+}
+
+// hand-off to contended broadcast consumers parked in Get
+func BenchmarkContractContended(b *testing.B) {
+	test := testSpec()
+	const consumers = 8
+	for _, impl := range contractImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var wg sync.WaitGroup
+			for i := 0; i < b.N; i++ {
+				f := impl.newFuture(Broadcast())
+				wg.Add(consumers)
+				for n := 0; n < consumers; n++ {
+					go func() {
+						defer wg.Done()
+						f.Get()
+					}()
+				}
+				f.SetValue(test.data)
+				wg.Wait()
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package future
+
+import (
+	"sync"
+	"time"
+)
+
+// ----------------------------------------------------------------------------
+// Demux
+// ----------------------------------------------------------------------------
+
+// future.DemuxStats is a snapshot of the metrics of a future.Demux.
+type DemuxStats struct {
+	Outstanding int    // requests awaiting a response
+	TimedOut    uint64 // requests failed per their deadline
+	Late        uint64 // responses to requests no longer outstanding
+	Unknown     uint64 // responses to request IDs never assigned
+}
+
+// slot is an outstanding request.
+type slot[Resp any] struct {
+	f     *futureResult[Resp]
+	timer *time.Timer // deadline - nil for none
+}
+
+// future.Demux correlates responses to requests by request ID, for
+// multiplexed protocols that respond out of order. Each registered request
+// is assigned an ID, and a future for its response; decoded responses are
+// routed to the future of their ID per Deliver.
+//
+// A request is outstanding until its future is completed, per Deliver, its
+// deadline, Cancel (per future.Canceller), or Close, at which point its ID
+// is released. The number of outstanding requests can be limited, per the
+// given saturation policy.
+//
+// Responses to released IDs are late, and are dropped. Responses to IDs
+// never assigned are unknown, and indicate a protocol error. Both are
+// reported by Deliver (and counted), but neither affects the Demux.
+//
+// A Demux is safe for concurrent use.
+type Demux[Resp any] struct {
+	policy SaturationPolicy
+	sem    chan struct{} // outstanding request capacity - nil for no limit
+	quit   chan struct{} // closed on Close - unblocks registrants
+
+	mu     sync.Mutex // guards below
+	next   uint64     // last assigned ID
+	slots  map[uint64]*slot[Resp]
+	closed error
+	stats  DemuxStats
+}
+
+// Creates a new Demux limited to maxOutstanding requests (0 for no limit),
+// registering requests in excess of the limit per the policy.
+func NewDemux[Resp any](maxOutstanding int, policy SaturationPolicy) *Demux[Resp] {
+	d := &Demux[Resp]{
+		policy: policy,
+		quit:   make(chan struct{}),
+		slots:  make(map[uint64]*slot[Resp]),
+	}
+	if maxOutstanding > 0 {
+		d.sem = make(chan struct{}, maxOutstanding)
+	}
+	return d
+}
+
+// Registers a new request, returning its ID, and a future for its response.
+// A positive wait is the deadline of the request, after which its future is
+// set with a *TimeoutError.
+//
+// If the request can not be registered, the returned ID is 0, and the
+// returned future is set with an error that errors.Is future.ErrRejected
+// (per Reject policy) or future.ErrShutdown (after Close).
+func (d *Demux[Resp]) Register(wait time.Duration) (id uint64, f TypedFuture[Resp]) {
+	g := NewFuture[Resp](AllowNil())
+	if err := d.acquire(); err != nil {
+		g.SetError(err)
+		return 0, g
+	}
+
+	d.mu.Lock()
+	if d.closed != nil {
+		d.mu.Unlock()
+		d.releaseCapacity()
+		g.SetError(&StateError{"Register", ErrShutdown})
+		return 0, g
+	}
+	d.next++
+	id = d.next
+	s := &slot[Resp]{f: g}
+	d.slots[id] = s
+	if wait > 0 {
+		s.timer = time.AfterFunc(wait, func() {
+			// note: the slot is claimed, and the timeout counted, before the
+			// future is completed, so Stats is current once it is
+			d.mu.Lock()
+			_, ok := d.slots[id]
+			if ok {
+				delete(d.slots, id)
+				d.stats.TimedOut++
+			}
+			d.mu.Unlock()
+
+			if ok {
+				d.free(s)
+				g.SetError(&TimeoutError{wait})
+			}
+		})
+	}
+	d.mu.Unlock()
+
+	g.OnComplete(func(TypedResult[Resp]) { d.release(id) })
+	return id, g
+}
+
+// Delivers the response (or error, if non-nil) of the request id. A nil
+// response (e.g. a Redis nil bulk reply) is a value result - as for a
+// future.Pipeline, request futures allow nil values, per future.AllowNil.
+// Returns an error that errors.Is future.ErrLateResponse or
+// future.ErrUnknownResponse if the request is not outstanding.
+func (d *Demux[Resp]) Deliver(id uint64, resp Resp, err error) error {
+	d.mu.Lock()
+	s, ok := d.slots[id]
+	if !ok {
+		e := d.stray(id)
+		d.mu.Unlock()
+		return e
+	}
+	delete(d.slots, id)
+	d.mu.Unlock()
+	d.free(s)
+
+	var e error
+	if err != nil {
+		e = s.f.SetError(err)
+	} else {
+		e = s.f.SetValue(resp)
+	}
+	if e != nil {
+		// completed concurrently - i.e. per Cancel
+		d.mu.Lock()
+		d.stats.Late++
+		d.mu.Unlock()
+		return &StateError{"Deliver", ErrLateResponse}
+	}
+	return nil
+}
+
+// Closes the demux: the futures of all outstanding requests are set with
+// err, or an error that errors.Is future.ErrShutdown if err is nil, and
+// subsequent registrations fail. E.g. Close the demux per a connection error.
+func (d *Demux[Resp]) Close(err error) {
+	if err == nil {
+		err = &StateError{"Close", ErrShutdown}
+	}
+
+	d.mu.Lock()
+	if d.closed != nil {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = err
+	close(d.quit)
+	slots := make([]*slot[Resp], 0, len(d.slots))
+	for id, s := range d.slots {
+		slots = append(slots, s)
+		delete(d.slots, id)
+	}
+	d.mu.Unlock()
+
+	for _, s := range slots {
+		d.free(s)
+		s.f.SetError(err)
+	}
+}
+
+// Returns a snapshot of the metrics of the demux.
+func (d *Demux[Resp]) Stats() DemuxStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := d.stats
+	stats.Outstanding = len(d.slots)
+	return stats
+}
+
+// acquire reserves capacity for a request, per the saturation policy.
+func (d *Demux[Resp]) acquire() error {
+	if d.sem == nil {
+		return nil
+	}
+	switch d.policy {
+	case Block:
+		select {
+		case d.sem <- struct{}{}:
+			return nil
+		case <-d.quit:
+			return &StateError{"Register", ErrShutdown}
+		}
+	default:
+		select {
+		case d.sem <- struct{}{}:
+			return nil
+		default:
+			return &StateError{"Register", ErrRejected}
+		}
+	}
+}
+
+// releaseCapacity releases capacity reserved per acquire.
+func (d *Demux[Resp]) releaseCapacity() {
+	if d.sem != nil {
+		<-d.sem
+	}
+}
+
+// release releases the ID (and capacity) of a completed request, unless
+// already claimed by its completer - i.e. of a request completed per Cancel.
+func (d *Demux[Resp]) release(id uint64) {
+	d.mu.Lock()
+	s, ok := d.slots[id]
+	delete(d.slots, id)
+	d.mu.Unlock()
+
+	if ok {
+		d.free(s)
+	}
+}
+
+// free stops the deadline, and releases the capacity, of the slot s, once
+// claimed (i.e. removed from d.slots) by exactly one of its completers.
+func (d *Demux[Resp]) free(s *slot[Resp]) {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	d.releaseCapacity()
+}
+
+// stray returns (and counts) the error of a response to id, which is not
+// outstanding. caller must hold d.mu.
+func (d *Demux[Resp]) stray(id uint64) error {
+	if id == 0 || id > d.next {
+		d.stats.Unknown++
+		return &StateError{"Deliver", ErrUnknownResponse}
+	}
+	d.stats.Late++
+	return &StateError{"Deliver", ErrLateResponse}
+}
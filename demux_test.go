@@ -0,0 +1,162 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+// responses delivered out of order
+// MUST be routed to the futures of their request IDs
+func TestDemuxOutOfOrder(t *testing.T) {
+	test := testSpec()
+	d := NewDemux[uint64](0, Reject)
+
+	const n = 100
+	ids := make([]uint64, n)
+	futures := make(map[uint64]TypedFuture[uint64], n)
+	for i := range ids {
+		id, f := d.Register(0)
+		ids[i] = id
+		futures[id] = f
+	}
+	if len(futures) != n {
+		t.Fatalf("expected %d distinct IDs - got %d", n, len(futures))
+	}
+
+	rand.Shuffle(n, func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			var err error
+			if id%10 == 0 {
+				err = test.err
+			}
+			if e := d.Deliver(id, id*id, err); e != nil {
+				t.Errorf("%d: unexpected Deliver error %v", id, e)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	for id, f := range futures {
+		result, timeout := f.TryGet(time.Second)
+		switch {
+		case timeout:
+			t.Fatalf("%d: timeout", id)
+		case id%10 == 0 && result.Error() != test.err:
+			t.Errorf("%d: expected spec error - got %v", id, result.Error())
+		case id%10 != 0 && result.Value() != id*id:
+			t.Errorf("%d: unexpected response %v", id, result.Value())
+		}
+	}
+	waitFor(t, "release", func() bool { return d.Stats().Outstanding == 0 })
+}
+
+// nil responses
+// MUST be value results, not errors
+func TestDemuxNilResponse(t *testing.T) {
+	d := NewDemux[[]byte](0, Reject)
+
+	id, f := d.Register(0)
+	if e := d.Deliver(id, nil, nil); e != nil {
+		t.Fatalf("unexpected Deliver error %v", e)
+	}
+	if result, timeout := f.TryGet(time.Second); timeout || result.IsError() || result.Value() != nil {
+		t.Errorf("expected nil value result - got %v", result)
+	}
+}
+
+// request past its deadline
+// MUST fail with a TimeoutError, release its ID, and make its response late
+func TestDemuxDeadline(t *testing.T) {
+	test := testSpec()
+	d := NewDemux[int](0, Reject)
+
+	id, f := d.Register(test.wait)
+	result, timeout := f.TryGet(time.Second)
+	if timeout || !errors.Is(result.Error(), ErrTimeout) {
+		t.Fatalf("expected ErrTimeout - got %v", result)
+	}
+	waitFor(t, "release", func() bool { return d.Stats().Outstanding == 0 })
+
+	if e := d.Deliver(id, 1, nil); !errors.Is(e, ErrLateResponse) {
+		t.Errorf("expected ErrLateResponse - got %v", e)
+	}
+	if e := d.Deliver(id+1, 1, nil); !errors.Is(e, ErrUnknownResponse) {
+		t.Errorf("expected ErrUnknownResponse - got %v", e)
+	}
+	if stats := d.Stats(); stats.TimedOut != 1 || stats.Late != 1 || stats.Unknown != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// requests in excess of the outstanding limit
+// MUST be rejected (per Reject), or wait for capacity (per Block)
+func TestDemuxLimit(t *testing.T) {
+	d := NewDemux[int](2, Reject)
+	id1, _ := d.Register(0)
+	d.Register(0)
+	if id, f := d.Register(0); id != 0 || !errors.Is(f.Get().Error(), ErrRejected) {
+		t.Fatalf("expected ErrRejected - got %d", id)
+	}
+	d.Deliver(id1, 1, nil)
+	waitFor(t, "capacity", func() bool {
+		id, _ := d.Register(0)
+		return id != 0
+	})
+
+	b := NewDemux[int](1, Block)
+	id, f := b.Register(0)
+	registered := make(chan uint64)
+	go func() {
+		id, _ := b.Register(0)
+		registered <- id
+	}()
+	select {
+	case <-registered:
+		t.Fatal("expected Register to block")
+	case <-time.After(10 * time.Millisecond):
+	}
+	f.(Canceller).Cancel(nil) // releases the ID
+	select {
+	case id2 := <-registered:
+		if id2 == 0 || id2 == id {
+			t.Errorf("unexpected ID %d", id2)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Register to unblock")
+	}
+}
+
+// Close
+// MUST fail outstanding requests, unblock & fail registrants
+func TestDemuxClose(t *testing.T) {
+	test := testSpec()
+	d := NewDemux[int](1, Block)
+
+	_, f := d.Register(0)
+	registered := make(chan TypedFuture[int])
+	go func() {
+		_, f := d.Register(0)
+		registered <- f
+	}()
+
+	d.Close(test.err)
+	d.Close(nil) // no-op
+	if result := f.Get(); result.Error() != test.err {
+		t.Errorf("expected spec error - got %v", result.Error())
+	}
+	if result := (<-registered).Get(); !errors.Is(result.Error(), ErrShutdown) {
+		t.Errorf("blocked: expected ErrShutdown - got %v", result.Error())
+	}
+	if _, g := d.Register(0); !errors.Is(g.Get().Error(), ErrShutdown) {
+		t.Error("expected ErrShutdown after Close")
+	}
+}
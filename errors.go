@@ -0,0 +1,138 @@
+package future
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ----------------------------------------------------------------------------
+// Sentinel Errors
+// ----------------------------------------------------------------------------
+
+// Contract violations and error results of futures are (per errors.Is) one
+// of the following sentinel errors, and can be further inspected (per
+// errors.As) as one of the structured error types below.
+var (
+	// future.ErrAlreadySet is returned by future.Provider#SetValue | SetError
+	// on a future that has already been set.
+	ErrAlreadySet = errors.New("already set")
+
+	// future.ErrCancelled is returned by future.Provider#SetValue | SetError
+	// on a cancelled future, and is the error result of a cancelled future.
+	ErrCancelled = errors.New("future cancelled")
+
+	// future.ErrTimeout is the error result of futures that did not complete
+	// within a specified wait duration.
+	ErrTimeout = errors.New("future timeout")
+
+	// future.ErrNilValue is returned by the package functions given a nil
+	// value (or future) where the contract does not permit it.
+	ErrNilValue = errors.New("nil value")
+
+	// future.ErrAlreadyConsumed is the error result of a one-shot future
+	// whose result has already been handed off to a consumer.
+	ErrAlreadyConsumed = errors.New("already consumed")
+
+	// future.ErrInUse is returned by Reset (or future.Pool#Put) of a future
+	// that is still in use by its consumer(s).
+	ErrInUse = errors.New("in use")
+
+	// future.ErrRecycled is returned to (and is the error result of) a stale
+	// consumer or provider of a future that has been recycled per future.Pool.
+	ErrRecycled = errors.New("recycled")
+
+	// future.ErrRejected is the error result of a task that was rejected
+	// by a saturated future.Executor.
+	ErrRejected = errors.New("rejected")
+
+	// future.ErrShutdown is the error result of a task that was submitted
+	// to (or was pending in) a future.Executor that is shut down.
+	ErrShutdown = errors.New("shut down")
+
+	// future.ErrLateResponse is returned by future.Demux#Deliver given the
+	// response to a request that is no longer outstanding - e.g. timed out.
+	ErrLateResponse = errors.New("late response")
+
+	// future.ErrUnknownResponse is returned by future.Demux#Deliver given the
+	// response to a request ID that was never assigned.
+	ErrUnknownResponse = errors.New("unknown response")
+
+	// future.ErrNoFutures is the error result of the combinators (e.g.
+	// future.Any, future.Race) given no futures.
+	ErrNoFutures = errors.New("no futures")
+
+	// future.ErrUnsolicited is the error of a future.Pipeline that decoded
+	// a response without a pending request.
+	ErrUnsolicited = errors.New("unsolicited response")
+)
+
+// ----------------------------------------------------------------------------
+// Error Types
+// ----------------------------------------------------------------------------
+
+// future.StateError is a violation of the future contract by the operation
+// Op, e.g. a SetValue of an already set future.
+type StateError struct {
+	Op  string // the operation - e.g. "SetValue"
+	Err error  // the sentinel error - e.g. future.ErrAlreadySet
+}
+
+func (e *StateError) Error() string {
+	return fmt.Sprintf("illegal state @ %s: %s", e.Op, e.Err)
+}
+
+func (e *StateError) Unwrap() error {
+	return e.Err
+}
+
+// future.CancelledError is the error result of a cancelled future, and is
+// (per errors.Is) future.ErrCancelled.
+type CancelledError struct {
+	Cause error // the (optional) cause given to Cancel
+}
+
+func (e *CancelledError) Error() string {
+	if e.Cause == nil {
+		return ErrCancelled.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrCancelled, e.Cause)
+}
+
+func (e *CancelledError) Is(target error) bool {
+	return target == ErrCancelled
+}
+
+func (e *CancelledError) Unwrap() error {
+	return e.Cause
+}
+
+// future.TimeoutError is the error result of a future that did not complete
+// within the Wait duration, and is (per errors.Is) future.ErrTimeout.
+type TimeoutError struct {
+	Wait time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s after %s", ErrTimeout, e.Wait)
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// future.RetryError is the error result of a future.Retry that failed: the
+// last error (Err) after the given number of Attempts. Err is a *TimeoutError
+// if the retry policy's Deadline expired.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("retry failed after %d attempt(s): %s", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
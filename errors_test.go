@@ -0,0 +1,76 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// structured errors
+// MUST be (errors.Is) their sentinel errors
+// MUST be (errors.As) their types
+func TestErrorTypes(t *testing.T) {
+	test := testSpec()
+
+	var se *StateError
+	var ce *CancelledError
+	var te *TimeoutError
+
+	futureObj := NewUntypedFuture()
+	futureObj.SetValue(test.data)
+	e := futureObj.SetError(test.err)
+	switch {
+	case !errors.Is(e, ErrAlreadySet):
+		t.Errorf("expected ErrAlreadySet - got %v", e)
+	case !errors.As(e, &se) || se.Op != "SetError":
+		t.Errorf("expected *StateError for SetError - got %v", e)
+	case e.Error() != "illegal state @ SetError: already set":
+		t.Errorf("unexpected error message %q", e.Error())
+	}
+
+	cancelled := NewUntypedFuture()
+	cancelled.Cancel(test.err)
+	e = cancelled.Get().Error()
+	switch {
+	case !errors.Is(e, ErrCancelled):
+		t.Errorf("expected ErrCancelled - got %v", e)
+	case !errors.Is(e, test.err):
+		t.Errorf("expected cause - got %v", e)
+	case !errors.As(e, &ce) || ce.Cause != test.err:
+		t.Errorf("expected *CancelledError with cause - got %v", e)
+	}
+
+	e = Race([]Future{NewUntypedFuture()}, time.Microsecond).Get().Error()
+	switch {
+	case !errors.Is(e, ErrTimeout):
+		t.Errorf("expected ErrTimeout - got %v", e)
+	case !errors.As(e, &te) || te.Wait != time.Microsecond:
+		t.Errorf("expected *TimeoutError with wait - got %v", e)
+	}
+
+	e = FlatMap(Async(func() (int, error) { return 1, nil }),
+		func(int) TypedFuture[int] { return nil }).Get().Error()
+	if !errors.Is(e, ErrNilValue) {
+		t.Errorf("expected ErrNilValue - got %v", e)
+	}
+
+	for op, f := range map[string]Future{"Any": Any([]Future{}), "Race": Race([]Future{})} {
+		e = f.Get().Error()
+		switch {
+		case !errors.Is(e, ErrNoFutures):
+			t.Errorf("%s: expected ErrNoFutures - got %v", op, e)
+		case !errors.As(e, &se) || se.Op != op:
+			t.Errorf("%s: expected *StateError for %s - got %v", op, op, e)
+		}
+	}
+
+	p := newTestPipeline()
+	defer p.Close()
+	p.Send("EXTRA").Get()
+	waitFor(t, "pipeline error", func() bool { return p.Err() != nil })
+	if e = p.Err(); !errors.Is(e, ErrUnsolicited) || !errors.As(e, &se) {
+		t.Errorf("expected *StateError of ErrUnsolicited - got %v", e)
+	}
+}
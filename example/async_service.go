@@ -3,6 +3,7 @@ package main
 import (
 	"future"
 	"log"
+	"sync/atomic"
 	"time"
 )
 
@@ -10,7 +11,7 @@ import (
 // creating and using an asynchronous service
 func main() {
 
-	server = startServer()
+	service = startService()
 	startClients()
 
 	var never chan struct{}
@@ -23,7 +24,8 @@ const (
 	loadFactor    = 10 * numClients // load factor
 )
 
-var server chan<- *asyncRequest
+// the service: requests are the request time, and responses the latency
+var service *future.AsyncService[time.Time, time.Duration]
 
 // --- the clients -----------------------------------
 
@@ -36,11 +38,11 @@ func startClients() {
 			var t0 time.Time = time.Now()
 
 			for {
-				// make the request and get the future.FutureResult
+				// make the request and get the future.TypedFuture
 				// note: calling time.Now in loop significantly impacts
 				//       performance. sampled results values do not reflect
 				//       actual future usage perf. cost.
-				fresult := callService(cid, time.Now())
+				fresult := service.Call(time.Now())
 
 				// TryGet the future result
 				// note: tryget & then get on timeout is non-optimal
@@ -73,51 +75,30 @@ func startClients() {
 
 // --- the service -----------------------------------
 
-type asyncRequest struct {
-	cid  uint
-	t0   time.Time
-	fobj interface{}
-}
-
-func callService(cid uint, t0 time.Time) future.Future {
-
-	// create the Future object
-	fobj := future.NewUntypedFuture()
-
-	// create an asyncRequest
-	// server will use future object to post its response
-	request := &asyncRequest{cid, time.Now(), fobj}
+// number of requests handled - see metrics
+var handled atomic.Uint64
 
-	// queue the request
-	server <- request
+func startService() *future.AsyncService[time.Time, time.Duration] {
+	// the handler
+	handler := func(t0 time.Time) (time.Duration, error) {
+		// sleep for fake service latency
+		time.Sleep(time.Duration(latencyFactor) * time.Nanosecond)
 
-	// Return the FutureResult of the Future object to the caller
-	return fobj
-}
+		// result is just the delta of t0 of request and time now
+		return time.Now().Sub(t0), nil
+	}
 
-func startServer() chan<- *asyncRequest {
-	c := make(chan *asyncRequest)
-	go func() {
-		for {
-			// process request queue or sleep if none pending
-			//
-			select {
-			case request := <-c:
-				// sleep for fake service latency
-				time.Sleep(time.Duration(latencyFactor) * time.Nanosecond)
-
-				// get the future object from the request
-				fobj := request.fobj
-
-				// result is just the delta of t0 of request and time now
-				t0 := request.t0
-				delta := time.Now().Sub(t0)
-				fobj.(future.Provider).SetValue(delta)
-
-			default:
-				time.Sleep(1 * time.Nanosecond)
+	// middleware: count handled requests, and log every loadFactor-th
+	metrics := func(next future.Handler[time.Time, time.Duration]) future.Handler[time.Time, time.Duration] {
+		return func(t0 time.Time) (time.Duration, error) {
+			delta, e := next(t0)
+			if n := handled.Add(1); n%loadFactor == 0 {
+				log.Printf("service: %d requests handled - latency %d nsec\n", n, delta)
 			}
+			return delta, e
 		}
-	}()
-	return c
+	}
+
+	// a single worker, with a queue per client
+	return future.NewAsyncService(1, numClients, future.Block, handler, metrics)
 }
@@ -0,0 +1,154 @@
+package future
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ----------------------------------------------------------------------------
+// Executor
+// ----------------------------------------------------------------------------
+
+// future.SaturationPolicy determines the behavior of future.Executor#Submit
+// when the executor's task queue is full.
+type SaturationPolicy int
+
+const (
+	// Submit fails fast: the returned future is set with future.ErrRejected.
+	Reject SaturationPolicy = iota
+
+	// Submit blocks the caller until there is capacity in the queue.
+	Block
+)
+
+// job is a queued task, ready to run or fail.
+type job struct {
+	run  func()          // runs the task and provides its result
+	fail func(err error) // cancels the task's future
+}
+
+// future.Executor runs submitted tasks on a fixed number of worker
+// goroutines, via a bounded queue of pending tasks, and returns futures
+// for their results.
+//
+// An Executor is safe for concurrent use.
+type Executor struct {
+	policy   SaturationPolicy
+	queue    chan job
+	quit     chan struct{} // closed on shutdown - unblocks submitters
+	quitOnce sync.Once
+	mu       sync.RWMutex // guards closed and sends to queue
+	closed   bool
+	abort    atomic.Bool // see ShutdownNow
+	workers  sync.WaitGroup
+}
+
+// Creates a new Executor, and starts its workers. A queue size of 0 hands
+// off tasks directly to idle workers.
+func NewExecutor(workers, queueSize int, policy SaturationPolicy) *Executor {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	e := &Executor{
+		policy: policy,
+		queue:  make(chan job, queueSize),
+		quit:   make(chan struct{}),
+	}
+	e.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go e.work()
+	}
+	return e
+}
+
+// Submits the task for execution, returning a future for its result.
+// See future.Execute.
+func (e *Executor) Submit(task func() (interface{}, error)) Future {
+	return Execute(e, task)
+}
+
+// future.Execute submits the task for execution by e, returning a future
+// for its result. The result of the task is provided per future.Async.
+//
+// If the task can not be queued, the returned future is set with an error
+// that errors.Is future.ErrRejected (per Reject policy) or future.ErrShutdown
+// (after Shutdown | ShutdownNow). If the returned future is cancelled before
+// the task runs, the task is not run.
+func Execute[T any](e *Executor, task func() (T, error)) TypedFuture[T] {
+	f := NewFuture[T]()
+	j := job{
+		run: func() {
+			if f.Err() == nil {
+				provide[T](f)(try(task))
+			}
+		},
+		fail: func(err error) { f.Cancel(err) },
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closed {
+		f.SetError(&StateError{"Submit", ErrShutdown})
+		return f
+	}
+	switch e.policy {
+	case Block:
+		select {
+		case e.queue <- j:
+		case <-e.quit:
+			f.SetError(&StateError{"Submit", ErrShutdown})
+		}
+	default:
+		select {
+		case e.queue <- j:
+		default:
+			f.SetError(&StateError{"Submit", ErrRejected})
+		}
+	}
+	return f
+}
+
+// Shutdown stops accepting tasks, and waits until all queued (and running)
+// tasks are done.
+func (e *Executor) Shutdown() {
+	e.shutdown()
+	e.workers.Wait()
+}
+
+// ShutdownNow stops accepting tasks, cancels the futures of all queued tasks
+// with an error that errors.Is future.ErrCancelled (and future.ErrShutdown),
+// and waits until running tasks are done.
+func (e *Executor) ShutdownNow() {
+	e.abort.Store(true)
+	e.shutdown()
+	e.workers.Wait()
+}
+
+// shutdown closes the queue once all in-flight submitters are done.
+func (e *Executor) shutdown() {
+	e.quitOnce.Do(func() {
+		close(e.quit)
+
+		e.mu.Lock()
+		e.closed = true
+		close(e.queue)
+		e.mu.Unlock()
+	})
+}
+
+// work runs (or, per ShutdownNow, fails) queued tasks until the queue is
+// closed and drained.
+func (e *Executor) work() {
+	defer e.workers.Done()
+	for j := range e.queue {
+		if e.abort.Load() {
+			j.fail(ErrShutdown)
+			continue
+		}
+		j.run()
+	}
+}
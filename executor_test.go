@@ -0,0 +1,155 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tasks submitted to an executor
+// MUST all run, with results provided via their futures
+func TestExecutorSubmit(t *testing.T) {
+	e := NewExecutor(4, 16, Block)
+	defer e.Shutdown()
+
+	futures := make([]TypedFuture[int], 100)
+	for i := range futures {
+		i := i
+		futures[i] = Execute(e, func() (int, error) { return i * i, nil })
+	}
+	for i, f := range futures {
+		if result, timeout := f.TryGet(time.Second); timeout || result.Value() != i*i {
+			t.Fatalf("%d: unexpected result", i)
+		}
+	}
+
+	test := testSpec()
+	if result := e.Submit(func() (interface{}, error) { return nil, test.err }).Get(); result.Error() != test.err {
+		t.Errorf("expected spec error - got %v", result.Error())
+	}
+}
+
+// tasks submitted to a saturated executor with Reject policy
+// MUST be rejected with ErrRejected
+func TestExecutorReject(t *testing.T) {
+	e := NewExecutor(1, 1, Reject)
+	defer e.Shutdown()
+
+	release := make(chan struct{})
+	blocker := func() (interface{}, error) { <-release; return 1, nil }
+
+	running := e.Submit(blocker) // occupies the worker
+	time.Sleep(10 * time.Millisecond)
+	queued := e.Submit(blocker) // occupies the queue
+	rejected := e.Submit(blocker)
+
+	if result, timeout := rejected.TryGet(time.Second); timeout || !errors.Is(result.Error(), ErrRejected) {
+		t.Error("expected ErrRejected")
+	}
+	close(release)
+	for _, f := range []Future{running, queued} {
+		if result, timeout := f.TryGet(time.Second); timeout || result.IsError() {
+			t.Error("expected value result")
+		}
+	}
+}
+
+// tasks submitted to a saturated executor with Block policy
+// MUST block the submitter until there is capacity
+func TestExecutorBlock(t *testing.T) {
+	e := NewExecutor(1, 0, Block)
+	defer e.Shutdown()
+
+	release := make(chan struct{})
+	e.Submit(func() (interface{}, error) { <-release; return 1, nil })
+
+	submitted := make(chan Future)
+	go func() { submitted <- e.Submit(func() (interface{}, error) { return 2, nil }) }()
+
+	select {
+	case <-submitted:
+		t.Fatal("expected Submit to block while saturated")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+	if result := (<-submitted).Get(); result.Value() != 2 {
+		t.Error("expected value result")
+	}
+}
+
+// Shutdown
+// MUST run all queued tasks
+// MUST reject subsequent tasks with ErrShutdown
+func TestExecutorShutdown(t *testing.T) {
+	e := NewExecutor(2, 100, Block)
+
+	var n atomic.Int32
+	futures := make([]Future, 50)
+	for i := range futures {
+		futures[i] = e.Submit(func() (interface{}, error) {
+			time.Sleep(time.Microsecond)
+			return n.Add(1), nil
+		})
+	}
+	e.Shutdown()
+
+	if n.Load() != 50 {
+		t.Errorf("expected all 50 tasks to run - got %d", n.Load())
+	}
+	if result := e.Submit(func() (interface{}, error) { return 1, nil }).Get(); !errors.Is(result.Error(), ErrShutdown) {
+		t.Errorf("expected ErrShutdown - got %v", result.Error())
+	}
+	e.Shutdown() // idempotent
+}
+
+// ShutdownNow
+// MUST cancel the futures of all queued tasks, without running them
+// MUST unblock submitters blocked per Block policy
+func TestExecutorShutdownNow(t *testing.T) {
+	e := NewExecutor(1, 10, Block)
+
+	release := make(chan struct{})
+	running := e.Submit(func() (interface{}, error) { <-release; return 1, nil })
+	time.Sleep(10 * time.Millisecond)
+
+	var ran atomic.Bool
+	queued := make([]Future, 10)
+	for i := range queued {
+		queued[i] = e.Submit(func() (interface{}, error) { ran.Store(true); return 1, nil })
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var blocked Future
+	go func() {
+		defer wg.Done()
+		blocked = e.Submit(func() (interface{}, error) { return 1, nil })
+	}()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	e.ShutdownNow()
+	wg.Wait()
+
+	if ran.Load() {
+		t.Error("unexpected run of queued task")
+	}
+	for i, f := range queued {
+		result := f.Get()
+		if !errors.Is(result.Error(), ErrCancelled) || !errors.Is(result.Error(), ErrShutdown) {
+			t.Errorf("%d: expected ErrCancelled by ErrShutdown - got %v", i, result.Error())
+		}
+	}
+	if result := running.Get(); result.IsError() {
+		t.Errorf("expected running task to complete - got %v", result.Error())
+	}
+	if result := blocked.Get(); !result.IsError() {
+		t.Error("expected blocked submit to fail")
+	}
+}
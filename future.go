@@ -4,13 +4,16 @@
 // The API can be used as either generic or type-safe manner:
 // the utility of the former case is limited to wrapping a
 // well-known channel/timer select pattern in a standard, tested, and
-// modularized manner; and the latter obviates the need for casts but
-// they do remain runtime mechanisms due to lack of support for generics.
+// modularized manner; and the latter obviates the need for casts.
 //
-// The package may provide type-safe variants for builtin types.  Users
-// can (and are free) to write their own variants for custom types, if
-// that is a requirement.  A reference implementation supporting untyped
-// futures is provided, and can be used via exported future.NewUntypedFuture.
+// The type-safe variants are the parameterized future.TypedFuture[T],
+// future.TypedResult[T], and future.TypedProvider[T] interfaces, with a
+// reference implementation available via exported future.NewFuture[T].
+// The untyped future.Future, future.Result, and future.Provider are
+// simply the T = interface{} case of these, and a reference implementation
+// supporting untyped futures is provided via exported future.NewUntypedFuture.
+// A lock-free alternative implementation, sans mutexes, is provided via
+// exported future.NewAtomicFuture[T] and future.NewUntypedAtomicFuture.
 //
 // API design was strongly inspired by Java's Futures.
 //
@@ -58,6 +61,14 @@
 //          }(response)
 //     }
 //
+// Or equivalently, with panics in the service call recovered as error results:
+//
+//     func RemoteServiceFoo (...) (response future.Future, ...) {
+//          return future.Async(func() (interface{}, error) {
+//              return invokeRemoteService(...)
+//          })
+//     }
+//
 // The consumer of the function | interface-method returning future.Future
 // objects can (a) fire-and-forgetall-but-error, (b) block until results are
 // provided, or (c) wait for a specified time (for cases such as meeting SLAs):
@@ -77,6 +88,11 @@
 //           }
 //       }(futureResponse, os.Stderr)
 //
+//       // or, sans the goroutine, given a future.CallbackFuture
+//       futureResponse.OnFailure(func(e error) {
+//           os.Stderr.Write(e.Error())
+//       })
+//
 //       ----------------------------
 //
 //       // idiom (b)
@@ -110,17 +126,45 @@
 //           ...
 //       }
 //
+// Rather than give up on the SLA per idiom (c), a consumer can hedge: per
+// future.Hedge, an identical backup request is made if the first has not
+// completed within a given delay, and the first success of either is returned.
+//
 // The behavior of future.Future is _only specified_ given the constraint that
 // the receiving party adheres to the following:
 //
 //  a) repeated subsequent calls to FutureResult#TryGet can be made if the
-//     calls result in timeouts.
+//     calls result in timeouts.  Likewise, repeated subsequent calls to
+//     future.ContextFuture#GetContext can be made if the calls return an error
+//     due to the context being done.
 //
 //  b) future.Future#Get must only be called once.  It may be called in isolation
-//     or can be called after one or more calls to TryGet, per timeout spec of
-//     `a` above).  Any other pattern of use is unspecified and is considered
+//     or can be called after one or more calls to TryGet or GetContext, per
+//     timeout spec of `a` above).  Any other pattern of use is unspecified and is considered
 //     a programmer error.
 //
+//  c) futures created with the future.Broadcast option are exempt from `b`:
+//     any number of Get | TryGet calls, from any number of goroutines, can be
+//     made and all return the same Result.
+//
+// A consumer that no longer requires the result may cancel futures supporting
+// the optional future.Canceller interface.  The cancellation is observable by
+// the provider via future.Provider#Cancelled and future.Provider#Err, and the
+// provider is expected to stop early (if possible) and abandon the hand-off:
+//
+//          go func(future future.Provider) {
+//              select {
+//              case <-future.Cancelled():
+//                  return // no longer needed
+//              case svcresp := <-remoteServiceResponse:
+//                  future.SetValue(svcresp)
+//              }
+//          }(response)
+//
+// Once cancelled, the future is completed with an error result that is
+// recognized by errors.Is(e, future.ErrCancelled), and subsequent provider
+// calls to SetValue | SetError return an error that errors.Is future.ErrCancelled.
+//
 // The Result interface reference obtained by the receiver per above is conceptually
 // a 'union' between an 'error' or 'value' (both regardless typed as interface{}).
 //
@@ -137,6 +181,7 @@
 package future
 
 import (
+	"context"
 	"time"
 )
 
@@ -144,26 +189,108 @@ import (
 // Future
 // ----------------------------------------------------------------------------
 
-// future.Future defines the api of future objects. Objects supporting this
+// future.TypedFuture defines the api of future objects. Objects supporting this
 // interface are created by the async function/service and returned to call site.
-type Future interface {
-	// Blocking get waits until future.Result is available.
-	Get() (r Result)
+type TypedFuture[T any] interface {
+	// Blocking get waits until future.TypedResult is available.
+	Get() (r TypedResult[T])
 
 	// Returns result or timeouts after specified wait duration
-	TryGet(wait time.Duration) (r Result, timeout bool)
+	TryGet(wait time.Duration) (r TypedResult[T], timeout bool)
+}
+
+// future.Future is the untyped future.TypedFuture.
+type Future = TypedFuture[interface{}]
+
+// future.TypedContextFuture is an optional interface supported by future
+// objects that can abandon the wait per a context.Context.
+type TypedContextFuture[T any] interface {
+	TypedFuture[T]
+
+	// Returns result or the cause of the context's cancellation (per
+	// context.Cause) if the ctx is done before the result is available.
+	GetContext(ctx context.Context) (r TypedResult[T], err error)
+}
+
+// future.ContextFuture is the untyped future.TypedContextFuture.
+type ContextFuture = TypedContextFuture[interface{}]
+
+// future.TypedSelectableFuture is an optional interface supported by future
+// objects that can be composed with other channels in select statements:
+//
+//       select {
+//       case <-futureResponse.Done():
+//           result, _ := futureResponse.Poll()
+//           ...
+//       case <-shutdown:
+//           ...
+//       }
+type TypedSelectableFuture[T any] interface {
+	TypedFuture[T]
+
+	// Returns a channel that is closed when the result is available.
+	Done() <-chan struct{}
+
+	// Non-blocking get returns the result and true if available, and
+	// otherwise nil and false. Poll has the same semantics as Get in
+	// regard to consuming the result of the future.
+	Poll() (r TypedResult[T], ok bool)
+}
+
+// future.SelectableFuture is the untyped future.TypedSelectableFuture.
+type SelectableFuture = TypedSelectableFuture[interface{}]
+
+// future.TypedCallbackFuture is an optional interface supported by future
+// objects that can notify registered callbacks on completion, obviating
+// the need to dedicate a goroutine (blocked in Get) per future.
+//
+// Callbacks run once the future is completed (set or cancelled), or, if it
+// is already completed, immediately on registration.  Callbacks run in the
+// order of registration, and are not run concurrently.  A callback that
+// panics does not prevent the subsequent callbacks from running.  Callbacks
+// do not consume the result of one-shot futures.
+//
+// Callbacks run synchronously, on the goroutine that completes the future
+// (per SetValue, SetError or Cancel), or, if it is already completed, on the
+// registering goroutine.  Callbacks (including those of Then, Map, etc.)
+// therefore run on the provider's goroutine - e.g. the reader of a Pipeline -
+// and MUST NOT block; a callback that blocks delays the provider, and any
+// subsequent callbacks.  Callbacks that may block should dispatch the work to
+// another goroutine (e.g. per an Executor).
+type TypedCallbackFuture[T any] interface {
+	TypedFuture[T]
+
+	// Registers a callback for any result.
+	OnComplete(fn func(r TypedResult[T]))
+
+	// Registers a callback for a value result.
+	OnSuccess(fn func(v T))
+
+	// Registers a callback for an error result.
+	OnFailure(fn func(e error))
+}
+
+// future.CallbackFuture is the untyped future.TypedCallbackFuture.
+type CallbackFuture = TypedCallbackFuture[interface{}]
+
+// future.Canceller is an optional interface supported by future objects
+// that allow the consumer to cancel the hand-off.
+type Canceller interface {
+	// Cancels the future with the given (optional) cause. Returns true if
+	// the future was cancelled, and false if it was already completed.
+	Cancel(cause error) bool
 }
 
 // ----------------------------------------------------------------------------
 // Future Result
 // ----------------------------------------------------------------------------
 
-// future.Result defines the type-generic future value results returned via a
-// future.Future.
-type Result interface {
+// future.TypedResult defines the type-safe future value results returned via a
+// future.TypedFuture.
+type TypedResult[T any] interface {
 	// Value result.
 	// Value of the Result - Value may be nil ONLY in case of Errors.
-	Value() interface{}
+	Value() T
 
 	// Error result.
 	// if Error is NOT nil, then Value must be nil.
@@ -173,20 +300,43 @@ type Result interface {
 	IsError() bool
 }
 
+// future.Result defines the type-generic future value results returned via a
+// future.Future.
+type Result = TypedResult[interface{}]
+
 // ----------------------------------------------------------------------------
 // Future Provider
 // ----------------------------------------------------------------------------
 
-// future.Provider defines the api for use by the provider of future.Results
-type Provider interface {
+// future.TypedProvider defines the api for use by the provider of
+// future.TypedResults
+//
+// Compliant implementations must be safe for concurrent use by multiple
+// provider goroutines: exactly one SetValue | SetError succeeds and all
+// other calls return a non-nil error.
+type TypedProvider[T any] interface {
 
 	// sets the value of the fchan Result
 	// Future.Value will be nil
-	// A non-nil error is returned if already set.
+	// An error that errors.Is future.ErrAlreadySet is returned if already set.
+	// An error that errors.Is future.ErrNilValue is returned if e is nil.
 	SetError(e error) error
 
 	// sets an erro fchan Result - note that nil values are NOT permitted.
 	// Future.Error will be nil
-	// A non-nil error is returned if already set.
-	SetValue(v interface{}) error
+	// An error that errors.Is future.ErrAlreadySet is returned if already set.
+	// An error that errors.Is future.ErrNilValue is returned if v is nil
+	// (unless the implementation explicitly permits nil values).
+	SetValue(v T) error
+
+	// Returns a channel that is closed if the future is cancelled by the
+	// consumer. Providers can use it to stop work that is no longer needed.
+	Cancelled() <-chan struct{}
+
+	// Returns nil if the future has not been cancelled, and otherwise the
+	// cancellation error (which errors.Is future.ErrCancelled).
+	Err() error
 }
+
+// future.Provider defines the api for use by the provider of future.Results
+type Provider = TypedProvider[interface{}]
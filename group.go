@@ -0,0 +1,101 @@
+package future
+
+import (
+	"sync"
+)
+
+// ----------------------------------------------------------------------------
+// Group
+// ----------------------------------------------------------------------------
+
+// future.GroupStats is a snapshot of the metrics of a future.Group.
+type GroupStats struct {
+	Calls        uint64 // calls of Do
+	Deduplicated uint64 // calls of Do handed an in-flight future
+	InFlight     int    // keys with a call in flight
+}
+
+// future.Group coalesces concurrent calls for the same key into a single
+// in-flight call, whose (broadcast) future is handed to all callers.
+//
+// A call is in flight only until it completes: its result, whether value or
+// error, is not cached, and the next call for its key makes a new call.
+//
+// A Group is safe for concurrent use.
+type Group[K comparable, T any] struct {
+	mu           sync.Mutex // guards below
+	calls        map[K]*futureResult[T]
+	count        uint64
+	deduplicated uint64
+}
+
+// Creates a new Group.
+func NewGroup[K comparable, T any]() *Group[K, T] {
+	return &Group[K, T]{
+		calls: make(map[K]*futureResult[T]),
+	}
+}
+
+// Creates a new Group of untyped futures keyed by string.
+func NewUntypedGroup() *Group[string, interface{}] {
+	return NewGroup[string, interface{}]()
+}
+
+// Returns the future of the in-flight call for the key, or makes a new call
+// of fn (on a new goroutine) if there is none. The result of fn is provided
+// per future.Async.
+//
+// The returned future is shared by all callers for the key (per
+// future.Broadcast): cancelling it, per future.Canceller, cancels it for all.
+func (g *Group[K, T]) Do(key K, fn func() (T, error)) TypedFuture[T] {
+	g.mu.Lock()
+	g.count++
+	if f, ok := g.calls[key]; ok {
+		select {
+		case <-f.Done(): // completed - not yet removed
+		default:
+			g.deduplicated++
+			g.mu.Unlock()
+			return f
+		}
+	}
+	f := NewFuture[T](Broadcast())
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	f.OnComplete(func(TypedResult[T]) { g.remove(key, f) })
+	go func() { provide[T](f)(try(fn)) }()
+	return f
+}
+
+// Forgets the in-flight call for the key (if any): the next call of Do for
+// the key makes a new call. Callers of the forgotten call are unaffected.
+func (g *Group[K, T]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.calls, key)
+}
+
+// Returns a snapshot of the metrics of the group.
+func (g *Group[K, T]) Stats() GroupStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return GroupStats{
+		Calls:        g.count,
+		Deduplicated: g.deduplicated,
+		InFlight:     len(g.calls),
+	}
+}
+
+// remove removes the completed call f for the key, unless it has been
+// forgotten (and possibly replaced by a new call).
+func (g *Group[K, T]) remove(key K, f *futureResult[T]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+}
@@ -0,0 +1,137 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrent calls for the same key
+// MUST share a single in-flight call and its result
+func TestGroupDedup(t *testing.T) {
+	g := NewGroup[int, string]()
+
+	release := make(chan struct{})
+	var calls int32
+	fn := func() (string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "v", nil
+	}
+
+	const n = 10
+	futures := make([]TypedFuture[string], n)
+	for i := range futures {
+		futures[i] = g.Do(1, fn)
+	}
+	other := g.Do(2, fn)
+	if stats := g.Stats(); stats.Calls != n+1 || stats.Deduplicated != n-1 || stats.InFlight != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	close(release)
+
+	var wg sync.WaitGroup
+	for i, f := range append(futures, other) {
+		wg.Add(1)
+		go func(i int, f TypedFuture[string]) {
+			defer wg.Done()
+			if result, timeout := f.TryGet(time.Second); timeout || result.Value() != "v" {
+				t.Errorf("%d: unexpected result %v", i, result)
+			}
+		}(i, f)
+	}
+	wg.Wait()
+	if c := atomic.LoadInt32(&calls); c != 2 {
+		t.Errorf("expected 2 calls - got %d", c)
+	}
+}
+
+// completed calls (value or error)
+// MUST not be cached
+func TestGroupNoCache(t *testing.T) {
+	test := testSpec()
+	g := NewUntypedGroup()
+
+	var calls int32
+	fn := func() (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return nil, test.err
+		}
+		return test.data, nil
+	}
+	if result := g.Do("k", fn).Get(); result.Error() != test.err {
+		t.Fatalf("expected spec error - got %v", result.Error())
+	}
+	if result := g.Do("k", fn).Get(); result.IsError() {
+		t.Fatalf("expected new call after error - got %v", result.Error())
+	}
+	if c := atomic.LoadInt32(&calls); c != 2 {
+		t.Errorf("expected 2 calls - got %d", c)
+	}
+
+	// completion callbacks (removing the call) may lag Get
+	deadline := time.Now().Add(time.Second)
+	for g.Stats().InFlight != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if stats := g.Stats(); stats.InFlight != 0 || stats.Deduplicated != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// forgotten in-flight call
+// MUST not be handed to subsequent callers, nor affect its own callers
+func TestGroupForget(t *testing.T) {
+	g := NewGroup[string, int]()
+
+	release := make(chan struct{})
+	var calls int32
+	fn := func() (int, error) {
+		n := atomic.AddInt32(&calls, 1)
+		<-release
+		return int(n), nil
+	}
+
+	f1 := g.Do("k", fn)
+	g.Forget("k")
+	f2 := g.Do("k", fn)
+	if f1 == f2 {
+		t.Fatal("expected new call after Forget")
+	}
+	close(release)
+
+	r1, r2 := f1.Get(), f2.Get()
+	if r1.IsError() || r2.IsError() || r1.Value() == r2.Value() {
+		t.Errorf("expected distinct results - got %v & %v", r1.Value(), r2.Value())
+	}
+
+	// completion of the forgotten call must not remove the new call
+	block := make(chan struct{})
+	defer close(block)
+	f3 := g.Do("k", func() (int, error) { <-block; return 0, nil })
+	g.remove("k", f1.(*futureResult[int]))
+	if f4 := g.Do("k", fn); f4 != f3 {
+		t.Error("expected in-flight call to survive removal of a forgotten call")
+	}
+}
+
+// cancelled in-flight call
+// MUST be replaced by a new call
+func TestGroupCancel(t *testing.T) {
+	g := NewUntypedGroup()
+
+	block := make(chan struct{})
+	defer close(block)
+	f := g.Do("k", func() (interface{}, error) { <-block; return nil, nil })
+	f.(Canceller).Cancel(nil)
+	if result := f.Get(); !errors.Is(result.Error(), ErrCancelled) {
+		t.Errorf("expected ErrCancelled - got %v", result.Error())
+	}
+	if g.Do("k", func() (interface{}, error) { return 1, nil }) == f {
+		t.Error("expected new call after cancellation")
+	}
+}
@@ -0,0 +1,226 @@
+package future
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ----------------------------------------------------------------------------
+// Hedger
+// ----------------------------------------------------------------------------
+
+// future.HedgeStats is a snapshot of the metrics of a future.Hedger.
+type HedgeStats struct {
+	Calls    uint64   // hedged calls made
+	Hedged   uint64   // calls that made more than one attempt
+	Attempts uint64   // attempts made, across all calls
+	Failures uint64   // calls that failed, with all attempts failed
+	Wins     []uint64 // wins by attempt - Wins[0] is the first attempt's
+}
+
+// future.Hedger makes hedged calls (see future.HedgeWith) with a given delay
+// and limit on attempts, and keeps metrics of how often hedging fired and
+// which attempt won.
+//
+// A Hedger is safe for concurrent use.
+type Hedger struct {
+	delay       time.Duration
+	maxAttempts int
+	calls       atomic.Uint64
+	hedged      atomic.Uint64
+	attempts    atomic.Uint64
+	failures    atomic.Uint64
+	wins        []atomic.Uint64 // by attempt
+}
+
+// Creates a new Hedger, making at most maxAttempts (>= 1) attempts per call,
+// each after delay if no earlier attempt has succeeded.
+func NewHedger(delay time.Duration, maxAttempts int) *Hedger {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &Hedger{
+		delay:       delay,
+		maxAttempts: maxAttempts,
+		wins:        make([]atomic.Uint64, maxAttempts),
+	}
+}
+
+// Makes a hedged call of fn. See future.HedgeWith.
+func (h *Hedger) Hedge(fn func() Future) Future {
+	return HedgeWith(h, fn)
+}
+
+// Returns a snapshot of the metrics of the hedger.
+func (h *Hedger) Stats() HedgeStats {
+	stats := HedgeStats{
+		Calls:    h.calls.Load(),
+		Hedged:   h.hedged.Load(),
+		Attempts: h.attempts.Load(),
+		Failures: h.failures.Load(),
+		Wins:     make([]uint64, len(h.wins)),
+	}
+	for i := range h.wins {
+		stats.Wins[i] = h.wins[i].Load()
+	}
+	return stats
+}
+
+// future.Hedge makes a hedged call of fn, an attempt producing a future,
+// per a new future.Hedger. See future.HedgeWith.
+func Hedge[T any](delay time.Duration, maxAttempts int, fn func() TypedFuture[T]) TypedFuture[T] {
+	return HedgeWith(NewHedger(delay, maxAttempts), fn)
+}
+
+// future.HedgeWith makes a hedged call of fn, an attempt producing a future:
+// fn is run, and run again (up to the hedger's limit on attempts) each time
+// the delay elapses without a successful attempt. A failed attempt, with no
+// other attempt pending, starts the next attempt without delay.
+//
+// The returned future has the result of the first successful attempt, or
+// (if all attempts fail) an error joining the errors of all attempts. The
+// losing attempts are cancelled, if their futures support future.Canceller,
+// and are otherwise ignored. A panic in fn, or a nil future, fails the
+// attempt.
+//
+// Given an untyped fn - func() Future - the returned future is a
+// future.Future.
+func HedgeWith[T any](h *Hedger, fn func() TypedFuture[T]) TypedFuture[T] {
+	c := &hedge[T]{
+		h:  h,
+		fn: fn,
+		g:  NewFuture[T](),
+	}
+	h.calls.Add(1)
+	c.g.OnComplete(func(TypedResult[T]) { c.cancel() })
+	c.launch()
+	return c.g
+}
+
+// hedge is the state of a hedged call.
+type hedge[T any] struct {
+	h       *Hedger
+	fn      func() TypedFuture[T]
+	g       *futureResult[T]
+	settled atomic.Bool // the result is claimed, by a win or the last failure
+
+	mu       sync.Mutex // guards below
+	launched int
+	futures  []TypedFuture[T] // futures of launched attempts
+	timer    *time.Timer      // delay of the next attempt
+	errs     []error          // errors of failed attempts
+}
+
+// launch makes the next attempt, unless the call is complete or the
+// attempts are exhausted, and schedules the attempt after it.
+func (c *hedge[T]) launch() {
+	c.mu.Lock()
+	if c.done() || c.launched == c.h.maxAttempts {
+		c.mu.Unlock()
+		return
+	}
+	i := c.launched
+	c.launched++
+	if c.timer != nil {
+		c.timer.Stop() // a failed attempt restarts the delay
+	}
+	if c.launched < c.h.maxAttempts {
+		c.timer = time.AfterFunc(c.h.delay, c.launch)
+	}
+	c.mu.Unlock()
+
+	c.h.attempts.Add(1)
+	if i == 1 {
+		c.h.hedged.Add(1)
+	}
+
+	f, e := try(func() (TypedFuture[T], error) { return c.fn(), nil })
+	if e == nil && f == nil {
+		e = &StateError{"Hedge", ErrNilValue}
+	}
+	if e != nil {
+		c.failed(e)
+		return
+	}
+
+	c.mu.Lock()
+	c.futures = append(c.futures, f)
+	c.mu.Unlock()
+	if c.done() {
+		c.cancel() // completed while fn ran
+		return
+	}
+
+	whenComplete(f, func(r TypedResult[T]) {
+		if r.IsError() {
+			c.failed(r.Error())
+			return
+		}
+		if !c.settled.CompareAndSwap(false, true) {
+			return // lost to another attempt
+		}
+		// note: counted before completing the call, so that the stats are
+		// current once its result is available - and uncounted if the
+		// call was cancelled concurrently
+		c.h.wins[i].Add(1)
+		if c.g.complete("SetValue", r) != nil {
+			c.h.wins[i].Add(^uint64(0))
+		}
+	})
+}
+
+// failed records the error of a failed attempt. The call fails if all
+// attempts failed, or otherwise the next attempt is made if none is pending.
+func (c *hedge[T]) failed(e error) {
+	c.mu.Lock()
+	c.errs = append(c.errs, e)
+	failed := len(c.errs)
+	idle := failed == c.launched
+	var err error
+	if failed == c.h.maxAttempts {
+		err = errors.Join(c.errs...)
+	}
+	c.mu.Unlock()
+
+	switch {
+	case err != nil:
+		if !c.settled.CompareAndSwap(false, true) {
+			return
+		}
+		c.h.failures.Add(1) // see launch
+		if c.g.SetError(err) != nil {
+			c.h.failures.Add(^uint64(0))
+		}
+	case idle:
+		c.launch()
+	}
+}
+
+// cancel stops the delay of the next attempt, and cancels the futures of
+// launched attempts.
+func (c *hedge[T]) cancel() {
+	c.mu.Lock()
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	futures := c.futures
+	c.mu.Unlock()
+
+	for _, f := range futures {
+		if cf, ok := f.(Canceller); ok {
+			cf.Cancel(nil)
+		}
+	}
+}
+
+// done returns true if the call is complete.
+func (c *hedge[T]) done() bool {
+	select {
+	case <-c.g.Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,130 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowThenFast returns an attempt func whose first attempt is slow, and
+// subsequent attempts are fast. The futures of all attempts are recorded.
+func slowThenFast(slow time.Duration, v interface{}) (func() Future, func() []*futureResult[interface{}]) {
+	var mu sync.Mutex
+	var futures []*futureResult[interface{}]
+	fn := func() Future {
+		mu.Lock()
+		defer mu.Unlock()
+
+		f := NewUntypedFuture()
+		delay := time.Duration(0)
+		if len(futures) == 0 {
+			delay = slow
+		}
+		futures = append(futures, f)
+		time.AfterFunc(delay, func() { f.SetValue(v) })
+		return f
+	}
+	return fn, func() []*futureResult[interface{}] {
+		mu.Lock()
+		defer mu.Unlock()
+		return futures
+	}
+}
+
+// first attempt within the delay
+// MUST win, with no hedging
+func TestHedgeNoHedging(t *testing.T) {
+	test := testSpec()
+	h := NewHedger(time.Second, 3)
+
+	fn, futures := slowThenFast(0, test.data)
+	if result, timeout := h.Hedge(fn).TryGet(time.Second); timeout || result.IsError() {
+		t.Fatalf("expected success - got %v", result)
+	}
+	if n := len(futures()); n != 1 {
+		t.Errorf("expected 1 attempt - got %d", n)
+	}
+	stats := h.Stats()
+	if stats.Calls != 1 || stats.Hedged != 0 || stats.Wins[0] != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// slow first attempt
+// MUST be hedged, lose to the backup attempt, and be cancelled
+func TestHedgeBackupWins(t *testing.T) {
+	test := testSpec()
+	h := NewHedger(test.wait, 3)
+
+	fn, futures := slowThenFast(time.Hour, test.data)
+	result, timeout := h.Hedge(fn).TryGet(time.Second)
+	if timeout || result.IsError() {
+		t.Fatalf("expected success - got %v", result)
+	}
+	fs := futures()
+	if len(fs) != 2 {
+		t.Fatalf("expected 2 attempts - got %d", len(fs))
+	}
+	// note: losing attempts are cancelled once the call has completed
+	waitFor(t, "losing attempt cancelled", func() bool { return errors.Is(fs[0].Err(), ErrCancelled) })
+	stats := h.Stats()
+	if stats.Hedged != 1 || stats.Attempts != 2 || stats.Wins[0] != 0 || stats.Wins[1] != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// all attempts failing
+// MUST fail with the joined errors of all attempts
+func TestHedgeAllFail(t *testing.T) {
+	test := testSpec()
+	other := errors.New("other")
+
+	var n int
+	var mu sync.Mutex
+	f := Hedge(time.Hour, 2, func() TypedFuture[int] {
+		mu.Lock()
+		defer mu.Unlock()
+		if n++; n == 1 {
+			return Async(func() (int, error) { return 0, test.err })
+		}
+		return Async(func() (int, error) { return 0, other })
+	})
+
+	// a failed attempt starts the next without delay
+	result, timeout := f.TryGet(time.Second)
+	if timeout {
+		t.Fatal("expected failure without waiting for the delay")
+	}
+	if !errors.Is(result.Error(), test.err) || !errors.Is(result.Error(), other) {
+		t.Errorf("expected joined errors - got %v", result.Error())
+	}
+
+	h := NewHedger(time.Hour, 1)
+	h.Hedge(func() Future { return nil }).Get()
+	if stats := h.Stats(); stats.Failures != 1 {
+		t.Errorf("expected 1 failure - got %+v", stats)
+	}
+}
+
+// cancelled hedged call
+// MUST cancel the pending attempts, and make no further attempts
+func TestHedgeCancel(t *testing.T) {
+	test := testSpec()
+	h := NewHedger(test.wait, 10)
+
+	fn, futures := slowThenFast(time.Hour, test.data)
+	f := h.Hedge(fn)
+	f.(Canceller).Cancel(nil)
+	time.Sleep(3 * test.wait)
+
+	fs := futures()
+	if len(fs) != 1 {
+		t.Fatalf("expected 1 attempt - got %d", len(fs))
+	}
+	if !errors.Is(fs[0].Err(), ErrCancelled) {
+		t.Errorf("expected pending attempt cancelled - got %v", fs[0].Err())
+	}
+}
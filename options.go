@@ -0,0 +1,46 @@
+package future
+
+// ----------------------------------------------------------------------------
+// Future Options
+// ----------------------------------------------------------------------------
+
+// future.Option configures the future objects created by the reference
+// implementation constructors (e.g. future.NewFuture).
+type Option func(*options)
+
+// options of the reference implementation future objects.
+type options struct {
+	broadcast bool // result is obtainable by any number of consumers
+	allowNil  bool // nil values are permitted
+}
+
+// Returns the options per the given Option(s).
+func newOptions(opts []Option) (o options) {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return
+}
+
+// future.Broadcast creates multi-consumer futures, where any number of
+// Get | TryGet calls, from any number of goroutines, all return the same
+// future.Result.
+//
+// By default futures are one-shot: the result is handed off to exactly
+// one consumer.
+func Broadcast() Option {
+	return func(o *options) {
+		o.broadcast = true
+	}
+}
+
+// future.AllowNil creates futures that permit nil values, e.g. a SetValue(nil)
+// of an untyped future. By default, per the future.Provider contract, nil
+// values are rejected with an error that errors.Is future.ErrNilValue.
+//
+// Note that nil errors are never permitted.
+func AllowNil() Option {
+	return func(o *options) {
+		o.allowNil = true
+	}
+}
@@ -0,0 +1,187 @@
+package future
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ----------------------------------------------------------------------------
+// Framing
+// ----------------------------------------------------------------------------
+
+// future.Encoder frames requests of type Req for a future.Pipeline.
+type Encoder[Req any] interface {
+	// Encodes the request to w. An error fails the pipeline.
+	Encode(w *bufio.Writer, req Req) error
+}
+
+// future.Decoder frames responses of type Resp for a future.Pipeline.
+type Decoder[Resp any] interface {
+	// Decodes the next response from r. An error that is (per errors.As)
+	// a *ReplyError is the error result of the request; any other error
+	// fails the pipeline. A nil response (e.g. a Redis nil bulk reply) is
+	// the value result of the request - pipeline futures allow nil values,
+	// per future.AllowNil.
+	Decode(r *bufio.Reader) (resp Resp, err error)
+}
+
+// future.ReplyError is returned by a future.Decoder for an error response
+// (e.g. a Redis "-ERR" reply). It is the error result of the request, and
+// the pipeline is unaffected.
+type ReplyError struct {
+	Err error
+}
+
+func (e *ReplyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ReplyError) Unwrap() error {
+	return e.Err
+}
+
+// ----------------------------------------------------------------------------
+// Pipeline
+// ----------------------------------------------------------------------------
+
+// future.Pipeline pipelines requests over a connection, without waiting for
+// the responses of prior requests, and correlates responses to requests per
+// their (FIFO) order: the futures of the requests are completed strictly in
+// the order of the requests.
+//
+// Once failed, per an error of the connection or its framing (including an
+// unsolicited response, per future.ErrUnsolicited), or Close, all pending and
+// subsequent requests fail with the error of the pipeline.
+//
+// A Pipeline is safe for concurrent use.
+type Pipeline[Req, Resp any] struct {
+	conn io.ReadWriter
+	enc  Encoder[Req]
+	dec  Decoder[Resp]
+
+	wmu sync.Mutex // guards w, and orders requests
+	w   *bufio.Writer
+
+	mu      sync.Mutex // guards below
+	pending []*futureResult[Resp]
+	err     error
+	done    chan struct{} // closed when the reader exits
+}
+
+// Creates a new Pipeline over the connection, and starts its reader.
+func NewPipeline[Req, Resp any](conn io.ReadWriter, enc Encoder[Req], dec Decoder[Resp]) *Pipeline[Req, Resp] {
+	p := &Pipeline[Req, Resp]{
+		conn: conn,
+		enc:  enc,
+		dec:  dec,
+		w:    bufio.NewWriter(conn),
+		done: make(chan struct{}),
+	}
+	go p.read()
+	return p
+}
+
+// Sends the request, returning a future for its response. If the pipeline
+// has failed, the returned future is set with the error of the pipeline.
+func (p *Pipeline[Req, Resp]) Send(req Req) TypedFuture[Resp] {
+	f := NewFuture[Resp](AllowNil())
+
+	p.wmu.Lock()
+	defer p.wmu.Unlock()
+
+	p.mu.Lock()
+	if p.err != nil {
+		err := p.err
+		p.mu.Unlock()
+		f.SetError(err)
+		return f
+	}
+	p.pending = append(p.pending, f)
+	p.mu.Unlock()
+
+	err := p.enc.Encode(p.w, req)
+	if err == nil {
+		err = p.w.Flush()
+	}
+	if err != nil {
+		p.fail(err)
+	}
+	return f
+}
+
+// Returns the number of requests pending a response.
+func (p *Pipeline[Req, Resp]) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.pending)
+}
+
+// Returns the error of the pipeline, or nil if it has not failed.
+func (p *Pipeline[Req, Resp]) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+// Close fails the pipeline with an error that errors.Is future.ErrShutdown,
+// and closes the connection, if an io.Closer, waiting for the reader to exit.
+func (p *Pipeline[Req, Resp]) Close() (err error) {
+	p.fail(&StateError{"Close", ErrShutdown})
+	if c, ok := p.conn.(io.Closer); ok {
+		err = c.Close()
+		<-p.done
+	}
+	return
+}
+
+// read decodes responses, and completes the pending requests in order,
+// until the pipeline fails.
+func (p *Pipeline[Req, Resp]) read() {
+	defer close(p.done)
+
+	r := bufio.NewReader(p.conn)
+	for {
+		resp, err := p.dec.Decode(r)
+		var reply *ReplyError
+		if err != nil && !errors.As(err, &reply) {
+			p.fail(err)
+			return
+		}
+
+		p.mu.Lock()
+		if len(p.pending) == 0 {
+			p.mu.Unlock()
+			p.fail(&StateError{"Decode", ErrUnsolicited})
+			return
+		}
+		f := p.pending[0]
+		p.pending[0] = nil
+		p.pending = p.pending[1:]
+		p.mu.Unlock()
+
+		// note: a cancelled request is dropped, but holds its place in order
+		provide[Resp](f)(resp, err)
+	}
+}
+
+// fail sets the error of the pipeline (if not already failed), and fails
+// all pending requests with it.
+func (p *Pipeline[Req, Resp]) fail(err error) {
+	p.mu.Lock()
+	if p.err != nil {
+		p.mu.Unlock()
+		return
+	}
+	p.err = err
+	pending := p.pending
+	p.pending = nil
+	p.mu.Unlock()
+
+	for _, f := range pending {
+		f.SetError(err)
+	}
+}
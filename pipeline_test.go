@@ -0,0 +1,257 @@
+/* white box tests */
+
+package future
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lineCodec frames requests & responses as lines. Responses prefixed with
+// "-" are error replies.
+type lineCodec struct{}
+
+func (lineCodec) Encode(w *bufio.Writer, req string) error {
+	_, err := w.WriteString(req + "\n")
+	return err
+}
+
+func (lineCodec) Decode(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\n")
+	if strings.HasPrefix(line, "-") {
+		return "", &ReplyError{errors.New(line[1:])}
+	}
+	return line, nil
+}
+
+// nilCodec decodes responses as byte slices, with "$nil" a nil response.
+type nilCodec struct{ lineCodec }
+
+func (c nilCodec) Decode(r *bufio.Reader) ([]byte, error) {
+	line, err := c.lineCodec.Decode(r)
+	if err != nil || line == "$nil" {
+		return nil, err
+	}
+	return []byte(line), nil
+}
+
+// fakeServer serves the line protocol over conn until it is closed:
+//
+//	ERR <msg>  replies with error <msg>
+//	HANG       reads, but never replies to, subsequent requests
+//	EXTRA      replies twice
+//	QUIT       closes the connection
+//	<other>    replies with the request
+func fakeServer(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	hang := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		req := strings.TrimSuffix(line, "\n")
+		if hang {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(req, "ERR "):
+			fmt.Fprintf(conn, "-%s\n", strings.TrimPrefix(req, "ERR "))
+		case req == "HANG":
+			hang = true
+		case req == "EXTRA":
+			fmt.Fprintf(conn, "%s\n%s\n", req, req)
+		case req == "QUIT":
+			return
+		default:
+			fmt.Fprintf(conn, "%s\n", req)
+		}
+	}
+}
+
+// newTestPipeline returns a pipeline connected to a fake server.
+func newTestPipeline() *Pipeline[string, string] {
+	client, server := net.Pipe()
+	go fakeServer(server)
+	return NewPipeline[string, string](client, lineCodec{}, lineCodec{})
+}
+
+// pipelined requests
+// MUST complete in order, with their own responses (or error replies)
+func TestPipelineFIFO(t *testing.T) {
+	p := newTestPipeline()
+	defer p.Close()
+
+	var completed []int
+	var mu sync.Mutex
+
+	const n = 100
+	futures := make([]TypedFuture[string], n)
+	for i := range futures {
+		req := fmt.Sprintf("req-%d", i)
+		if i%10 == 0 {
+			req = fmt.Sprintf("ERR err-%d", i)
+		}
+		i := i
+		futures[i] = p.Send(req)
+		futures[i].(TypedCallbackFuture[string]).OnComplete(func(TypedResult[string]) {
+			mu.Lock()
+			completed = append(completed, i)
+			mu.Unlock()
+		})
+	}
+	for i, f := range futures {
+		result, timeout := f.TryGet(time.Second)
+		if timeout {
+			t.Fatalf("%d: timeout", i)
+		}
+		if i%10 == 0 {
+			var reply *ReplyError
+			if !errors.As(result.Error(), &reply) || reply.Error() != fmt.Sprintf("err-%d", i) {
+				t.Errorf("%d: expected error reply - got %v", i, result.Error())
+			}
+			continue
+		}
+		if result.Value() != fmt.Sprintf("req-%d", i) {
+			t.Errorf("%d: unexpected response %q", i, result.Value())
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, c := range completed {
+		if i != c {
+			t.Fatalf("expected completion in order - got %v", completed)
+		}
+	}
+	if p.Len() != 0 || p.Err() != nil {
+		t.Errorf("expected no pending & no error - got %d & %v", p.Len(), p.Err())
+	}
+}
+
+// concurrent senders
+// MUST each get the response to their own request
+// note: run with -race
+func TestPipelineConcurrent(t *testing.T) {
+	p := newTestPipeline()
+	defer p.Close()
+
+	var wg sync.WaitGroup
+	for c := 0; c < 8; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				req := fmt.Sprintf("%d/%d", c, i)
+				if result, timeout := p.Send(req).TryGet(time.Second); timeout || result.Value() != req {
+					t.Errorf("%s: unexpected response %v", req, result)
+					return
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+}
+
+// nil responses
+// MUST be value results, not errors
+func TestPipelineNilResponse(t *testing.T) {
+	client, server := net.Pipe()
+	go fakeServer(server)
+	p := NewPipeline[string, []byte](client, lineCodec{}, nilCodec{})
+	defer p.Close()
+
+	nilResp := p.Send("$nil")
+	resp := p.Send("x")
+	if result, timeout := nilResp.TryGet(time.Second); timeout || result.IsError() || result.Value() != nil {
+		t.Errorf("expected nil value result - got %v", result)
+	}
+	if result, timeout := resp.TryGet(time.Second); timeout || string(result.Value()) != "x" {
+		t.Errorf("unexpected response %v", result)
+	}
+	if p.Err() != nil {
+		t.Errorf("unexpected pipeline error %v", p.Err())
+	}
+}
+
+// connection error
+// MUST fail all pending requests, and subsequent requests
+func TestPipelineConnError(t *testing.T) {
+	p := newTestPipeline()
+	defer p.Close()
+
+	first := p.Send("first")
+	p.Send("HANG")
+	pending := []TypedFuture[string]{p.Send("a"), p.Send("b")}
+	if result := first.Get(); result.Value() != "first" {
+		t.Fatalf("unexpected response %v", result)
+	}
+	if p.Len() != 3 {
+		t.Fatalf("expected 3 pending - got %d", p.Len())
+	}
+
+	p.conn.(net.Conn).Close()
+	for i, f := range pending {
+		result, timeout := f.TryGet(time.Second)
+		if timeout || !result.IsError() {
+			t.Errorf("%d: expected connection error - got %v", i, result)
+		}
+	}
+	if p.Err() == nil {
+		t.Error("expected pipeline error")
+	}
+	if result := p.Send("late").Get(); result.Error() != p.Err() {
+		t.Errorf("expected pipeline error - got %v", result.Error())
+	}
+}
+
+// server closing the connection, or replying unsolicited
+// MUST fail the pipeline
+func TestPipelineServerFailure(t *testing.T) {
+	p := newTestPipeline()
+	f := p.Send("QUIT")
+	if result, timeout := f.TryGet(time.Second); timeout || !result.IsError() {
+		t.Errorf("QUIT: expected error - got %v", result)
+	}
+	p.Close()
+
+	p = newTestPipeline()
+	defer p.Close()
+	p.Send("EXTRA").Get()
+	deadline := time.Now().Add(time.Second)
+	for p.Err() == nil && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !errors.Is(p.Err(), ErrUnsolicited) {
+		t.Errorf("EXTRA: expected ErrUnsolicited - got %v", p.Err())
+	}
+}
+
+// Close
+// MUST fail pending requests with ErrShutdown
+func TestPipelineClose(t *testing.T) {
+	p := newTestPipeline()
+	p.Send("HANG")
+	f := p.Send("x")
+	if err := p.Close(); err != nil {
+		t.Errorf("unexpected Close error %v", err)
+	}
+	if result := f.Get(); !errors.Is(result.Error(), ErrShutdown) {
+		t.Errorf("expected ErrShutdown - got %v", result.Error())
+	}
+	if result := p.Send("y").Get(); !errors.Is(result.Error(), ErrShutdown) {
+		t.Errorf("expected ErrShutdown after Close - got %v", result.Error())
+	}
+}
@@ -0,0 +1,154 @@
+package future
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ----------------------------------------------------------------------------
+// Future Pool
+// ----------------------------------------------------------------------------
+
+// future.Pool is a pool of reusable futures for high-throughput request
+// loops, where allocating a new future per request adds GC pressure.
+//
+// Futures obtained from the pool (as a future.PooledFuture) must be returned
+// to the pool (via Put) only once all consumers are done with the future.
+// Put fails if the future is detectably still in use (see Reset), and once
+// returned, any use of the future by a stale consumer or provider fails with
+// future.ErrRecycled - including once the future is reused.
+//
+// A Pool is safe for concurrent use.
+type Pool[T any] struct {
+	opts []Option
+	pool sync.Pool
+}
+
+// Creates a new pool of futures created with the given options, per
+// future.NewFuture.
+func NewPool[T any](opts ...Option) *Pool[T] {
+	return &Pool[T]{opts: opts}
+}
+
+// Creates a new pool of untyped futures.
+func NewUntypedPool(opts ...Option) *Pool[interface{}] {
+	return NewPool[interface{}](opts...)
+}
+
+// Returns a (new or recycled) future that is not set.
+func (p *Pool[T]) Get() PooledFuture[T] {
+	if f, ok := p.pool.Get().(*futureResult[T]); ok {
+		f.pooled.Store(false)
+		return PooledFuture[T]{f, f.gen.Load()}
+	}
+	return PooledFuture[T]{NewFuture[T](p.opts...), 0}
+}
+
+// Returns the completed future f, obtained via Get, to the pool. A non-nil
+// error is returned, and f is not pooled, if f is still in use, or has
+// already been returned.
+func (p *Pool[T]) Put(f PooledFuture[T]) error {
+	if e := f.f.recycle(f.gen); e != nil {
+		return e
+	}
+	p.pool.Put(f.f)
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// Pooled Future
+// ----------------------------------------------------------------------------
+
+// future.PooledFuture is a future obtained from a future.Pool, valid for a
+// single use of the pooled future object: the handle carries the generation
+// of the object, which is checked by every consumer and provider call, so
+// that once the object is returned to the pool (and possibly reused by
+// another request), calls via the stale handle fail with future.ErrRecycled
+// rather than act on the new request.
+//
+// Stale consumers get an ErrRecycled error result. Stale providers get an
+// ErrRecycled error from SetValue | SetError | Err, and a closed Cancelled
+// channel. Stale callbacks run immediately, with an ErrRecycled error result.
+//
+// PooledFuture supports future.TypedFuture and future.TypedProvider (and the
+// optional future.TypedSelectableFuture, future.TypedContextFuture,
+// future.TypedCallbackFuture, and future.Canceller interfaces), and is
+// (cheaply) copied by value. The zero PooledFuture is not valid.
+type PooledFuture[T any] struct {
+	f   *futureResult[T]
+	gen uint64
+}
+
+// interface: future.Future#Get
+func (h PooledFuture[T]) Get() TypedResult[T] {
+	return h.f.getGen(h.gen)
+}
+
+// interface: future.Future#TryGet
+func (h PooledFuture[T]) TryGet(ns time.Duration) (TypedResult[T], bool) {
+	return h.f.tryGetGen(h.gen, ns)
+}
+
+// interface: future.ContextFuture#GetContext
+func (h PooledFuture[T]) GetContext(ctx context.Context) (TypedResult[T], error) {
+	return h.f.getContextGen(h.gen, ctx)
+}
+
+// interface: future.SelectableFuture#Done
+func (h PooledFuture[T]) Done() <-chan struct{} {
+	return h.f.doneGen(h.gen)
+}
+
+// interface: future.SelectableFuture#Poll
+func (h PooledFuture[T]) Poll() (TypedResult[T], bool) {
+	return h.f.pollGen(h.gen)
+}
+
+// interface: future.Canceller#Cancel
+func (h PooledFuture[T]) Cancel(cause error) bool {
+	return h.f.cancelGen(h.gen, cause)
+}
+
+// interface: future.CallbackFuture#OnComplete
+func (h PooledFuture[T]) OnComplete(fn func(r TypedResult[T])) {
+	h.f.onCompleteGen(h.gen, fn)
+}
+
+// interface: future.CallbackFuture#OnSuccess
+func (h PooledFuture[T]) OnSuccess(fn func(v T)) {
+	h.OnComplete(func(r TypedResult[T]) {
+		if !r.IsError() {
+			fn(r.Value())
+		}
+	})
+}
+
+// interface: future.CallbackFuture#OnFailure
+func (h PooledFuture[T]) OnFailure(fn func(e error)) {
+	h.OnComplete(func(r TypedResult[T]) {
+		if r.IsError() {
+			fn(r.Error())
+		}
+	})
+}
+
+// interface: future.Provider#SetValue
+func (h PooledFuture[T]) SetValue(v T) error {
+	return h.f.setValueGen(h.gen, v)
+}
+
+// interface: future.Provider#SetError
+func (h PooledFuture[T]) SetError(e error) error {
+	return h.f.setErrorGen(h.gen, e)
+}
+
+// interface: future.Provider#Cancelled
+func (h PooledFuture[T]) Cancelled() <-chan struct{} {
+	return h.f.cancelledGen(h.gen)
+}
+
+// interface: future.Provider#Err
+func (h PooledFuture[T]) Err() error {
+	return h.f.errGen(h.gen)
+}
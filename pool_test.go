@@ -0,0 +1,226 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pooled future set, consumed, and returned to the pool
+// MUST be reused as a future not set
+func TestPoolReuse(t *testing.T) {
+	test := testSpec()
+
+	pool := NewUntypedPool()
+	f := pool.Get()
+	f.SetValue(test.data)
+	f.Get()
+	if e := pool.Put(f); e != nil {
+		t.Fatalf("unexpected Put error %v", e)
+	}
+
+	g := pool.Get()
+	if _, timeout := g.TryGet(0); !timeout {
+		t.Fatal("expected recycled future not to be set")
+	}
+	g.SetError(test.err)
+	if result := g.Get(); result.Error() != test.err {
+		t.Errorf("expected spec error - got %v", result.Error())
+	}
+}
+
+// Put of futures still in use
+// MUST fail with ErrInUse
+func TestPoolPutInUse(t *testing.T) {
+	test := testSpec()
+
+	pool := NewUntypedPool()
+
+	f := pool.Get()
+	if e := pool.Put(f); !errors.Is(e, ErrInUse) {
+		t.Errorf("not set: expected ErrInUse - got %v", e)
+	}
+	f.SetValue(test.data)
+	if e := pool.Put(f); !errors.Is(e, ErrInUse) {
+		t.Errorf("not consumed: expected ErrInUse - got %v", e)
+	}
+}
+
+// Put of a broadcast future with a consumer waiting
+// MUST fail with ErrInUse
+func TestPoolPutWaiting(t *testing.T) {
+	f := NewFuture[int](Broadcast())
+	f.SetValue(1)
+
+	// simulate a consumer between enter & exit
+	f.enter(f.gen.Load())
+	if e := f.Reset(); !errors.Is(e, ErrInUse) {
+		t.Errorf("expected ErrInUse - got %v", e)
+	}
+	f.exit()
+	if e := f.Reset(); e != nil {
+		t.Errorf("unexpected Reset error %v", e)
+	}
+}
+
+// stale consumer & provider of a recycled future
+// MUST fail with ErrRecycled
+func TestPoolStaleUse(t *testing.T) {
+	test := testSpec()
+
+	pool := NewPool[[]byte]()
+	f := pool.Get()
+	f.SetValue(test.data)
+	f.Get()
+	pool.Put(f)
+
+	if result := f.Get(); !errors.Is(result.Error(), ErrRecycled) {
+		t.Errorf("Get: expected ErrRecycled - got %v", result.Error())
+	}
+	if result, timeout := f.TryGet(test.wait); timeout || !errors.Is(result.Error(), ErrRecycled) {
+		t.Error("TryGet: expected ErrRecycled")
+	}
+	if e := f.SetValue(test.data); !errors.Is(e, ErrRecycled) {
+		t.Errorf("SetValue: expected ErrRecycled - got %v", e)
+	}
+	if f.Cancel(nil) {
+		t.Error("Cancel: expected false")
+	}
+	if e := pool.Put(f); !errors.Is(e, ErrRecycled) {
+		t.Errorf("Put: expected ErrRecycled - got %v", e)
+	}
+}
+
+// stale consumer & provider of a recycled future, reused by a new request
+// MUST fail with ErrRecycled
+// MUST NOT affect (or observe) the new request
+func TestPoolStaleUseAfterReuse(t *testing.T) {
+	test := testSpec()
+
+	// note: sync.Pool may drop recycled futures (e.g. per -race), so
+	// recycle until the future is reused
+	pool := NewPool[int]()
+	var f, g PooledFuture[int]
+	for i := 0; g.f == nil || g.f != f.f; i++ {
+		if i == 100 {
+			t.Fatal("expected the pool to reuse the future")
+		}
+		f = pool.Get()
+		f.SetValue(1)
+		f.Get()
+		pool.Put(f)
+		g = pool.Get()
+	}
+
+	// stale provider
+	if e := f.SetValue(99); !errors.Is(e, ErrRecycled) {
+		t.Errorf("SetValue: expected ErrRecycled - got %v", e)
+	}
+	if e := f.SetError(test.err); !errors.Is(e, ErrRecycled) {
+		t.Errorf("SetError: expected ErrRecycled - got %v", e)
+	}
+	if f.Cancel(nil) {
+		t.Error("Cancel: expected false")
+	}
+	if e := f.Err(); !errors.Is(e, ErrRecycled) {
+		t.Errorf("Err: expected ErrRecycled - got %v", e)
+	}
+	select {
+	case <-f.Cancelled():
+	default:
+		t.Error("Cancelled: expected closed channel")
+	}
+	if _, timeout := g.TryGet(0); !timeout {
+		t.Fatal("expected new request not to be set by stale provider")
+	}
+	if g.Err() != nil {
+		t.Errorf("expected new request not to be cancelled - got %v", g.Err())
+	}
+
+	// stale consumer
+	g.SetValue(2)
+	if result := f.Get(); !errors.Is(result.Error(), ErrRecycled) {
+		t.Errorf("Get: expected ErrRecycled - got %v", result)
+	}
+	if result, timeout := f.TryGet(test.wait); timeout || !errors.Is(result.Error(), ErrRecycled) {
+		t.Errorf("TryGet: expected ErrRecycled - got %v", result)
+	}
+	if result, ok := f.Poll(); !ok || !errors.Is(result.Error(), ErrRecycled) {
+		t.Errorf("Poll: expected ErrRecycled - got %v", result)
+	}
+	var stale TypedResult[int]
+	f.OnComplete(func(r TypedResult[int]) { stale = r })
+	if stale == nil || !errors.Is(stale.Error(), ErrRecycled) {
+		t.Errorf("OnComplete: expected ErrRecycled - got %v", stale)
+	}
+	if e := pool.Put(f); !errors.Is(e, ErrRecycled) {
+		t.Errorf("Put: expected ErrRecycled - got %v", e)
+	}
+
+	// the new request is unaffected
+	if result := g.Get(); result.IsError() || result.Value() != 2 {
+		t.Errorf("expected value 2 for the new request - got %v", result)
+	}
+	if e := pool.Put(g); e != nil {
+		t.Errorf("unexpected Put error %v", e)
+	}
+}
+
+// concurrent request loops over a shared pool
+// note: run with -race
+func TestPoolConcurrent(t *testing.T) {
+	pool := NewPool[int]()
+
+	var wg sync.WaitGroup
+	for c := 0; c < 8; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				f := pool.Get()
+				go f.SetValue(i)
+				result, timeout := f.TryGet(time.Second)
+				if timeout || result.Value() != i {
+					t.Errorf("client %d: unexpected result for request %d", c, i)
+					return
+				}
+				if e := pool.Put(f); e != nil {
+					t.Errorf("client %d: unexpected Put error %v", c, e)
+					return
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+}
+
+/// benchmarks /////////////////////////////////////////////////////////
+
+// request loop with pooled futures
+func BenchmarkPoolRequestLoop(b *testing.B) {
+	test := testSpec()
+	pool := NewUntypedPool()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f := pool.Get()
+		f.SetValue(test.data)
+		f.Get()
+		pool.Put(f)
+	}
+}
+
+// baseline: request loop with new futures
+func BenchmarkNewFutureRequestLoop(b *testing.B) {
+	test := testSpec()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f := NewUntypedFuture()
+		f.SetValue(test.data)
+		f.Get()
+	}
+}
@@ -0,0 +1,235 @@
+package future
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ----------------------------------------------------------------------------
+// Backoff
+// ----------------------------------------------------------------------------
+
+// future.Backoff returns the delay before the next attempt of a future.Retry,
+// given the number of attempts made so far (>= 1), and the last delay (0
+// before the first retry).
+type Backoff func(attempt int, last time.Duration) time.Duration
+
+// future.ConstantBackoff delays each retry by d.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration {
+		return d
+	}
+}
+
+// future.ExponentialBackoff delays the first retry by base, doubling the
+// delay for each subsequent retry, up to max (if max > 0).
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < math.MaxInt64/2 && (max <= 0 || d < max); i++ {
+			d *= 2
+		}
+		if max > 0 && d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// future.DecorrelatedJitterBackoff delays each retry by a random duration
+// between base and 3x the last delay, up to max (if max > 0). Jitter spreads
+// the retries of concurrent callers of a recovering service.
+func DecorrelatedJitterBackoff(base, max time.Duration) Backoff {
+	return func(_ int, last time.Duration) time.Duration {
+		if last < base {
+			last = base
+		}
+		d := base
+		if span := 3*last - base; span > 0 {
+			d += time.Duration(rand.Int63n(int64(span)))
+		}
+		if max > 0 && d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// ----------------------------------------------------------------------------
+// Retry
+// ----------------------------------------------------------------------------
+
+// future.DefaultMaxAttempts is the limit on attempts of a future.RetryPolicy
+// that is otherwise unbounded, and immediate: sans MaxAttempts, Deadline,
+// and Backoff.
+const DefaultMaxAttempts = 3
+
+// future.RetryPolicy determines if, and when, future.Retry re-runs a failed
+// attempt. A zero RetryPolicy retries all errors, immediately, up to
+// future.DefaultMaxAttempts attempts: a policy retrying without limit must
+// specify a Deadline, or a Backoff.
+type RetryPolicy struct {
+	MaxAttempts int                  // limit on attempts - 0 for no limit
+	Deadline    time.Duration        // limit on total duration - 0 for no limit
+	Backoff     Backoff              // delay between attempts - nil for none
+	Retryable   func(err error) bool // retryable errors - nil for all
+}
+
+// future.Attempted is an optional interface supported by the results of
+// future.Retry, reporting the number of attempts made.
+type Attempted interface {
+	Attempts() int
+}
+
+// retryResult is a result of future.Retry, supporting future.Attempted.
+type retryResult[T any] struct {
+	TypedResult[T]
+	attempts int
+}
+
+// interface: future.Attempted#Attempts
+func (r *retryResult[T]) Attempts() int {
+	return r.attempts
+}
+
+// future.Retry runs fn, an attempt producing a future, and re-runs it per
+// the policy until an attempt succeeds, the error of an attempt is not
+// retryable, the attempts are exhausted, or the deadline expires.
+//
+// The returned future has the result of the successful attempt, or a
+// *RetryError carrying the last error, and its result supports
+// future.Attempted. A panic in fn, or a nil future, fails the attempt.
+// Cancelling the returned future (or expiry of the deadline) cancels the
+// pending attempt, if its future supports future.Canceller.
+//
+// Given an untyped fn - func() Future - the returned future is a
+// future.Future.
+func Retry[T any](policy RetryPolicy, fn func() TypedFuture[T]) TypedFuture[T] {
+	if policy.MaxAttempts <= 0 && policy.Deadline <= 0 && policy.Backoff == nil {
+		policy.MaxAttempts = DefaultMaxAttempts // not a hot loop
+	}
+	r := &retry[T]{
+		policy: policy,
+		fn:     fn,
+		g:      NewFuture[T](),
+		start:  time.Now(),
+	}
+	if policy.Deadline > 0 {
+		timer := time.AfterFunc(policy.Deadline, r.expire)
+		r.g.OnComplete(func(TypedResult[T]) { timer.Stop() })
+	}
+	r.g.OnComplete(func(TypedResult[T]) { r.cancelPending() })
+	r.attempt()
+	return r.g
+}
+
+// retry is the state of a future.Retry.
+type retry[T any] struct {
+	policy RetryPolicy
+	fn     func() TypedFuture[T]
+	g      *futureResult[T]
+	start  time.Time
+
+	mu       sync.Mutex // guards below
+	attempts int
+	delay    time.Duration  // last backoff delay
+	pending  TypedFuture[T] // future of the last attempt
+}
+
+// attempt runs fn, and handles the result of its future.
+func (r *retry[T]) attempt() {
+	if r.done() {
+		return
+	}
+	f, e := try(func() (TypedFuture[T], error) { return r.fn(), nil })
+	if e == nil && f == nil {
+		e = &StateError{"Retry", ErrNilValue}
+	}
+
+	r.mu.Lock()
+	r.attempts++
+	n := r.attempts
+	r.pending = f
+	r.mu.Unlock()
+
+	if e != nil {
+		r.failed(n, e)
+		return
+	}
+	if r.done() {
+		r.cancelPending() // completed while fn ran
+		return
+	}
+	whenComplete(f, func(res TypedResult[T]) {
+		if res.IsError() {
+			r.failed(n, res.Error())
+			return
+		}
+		r.g.complete("SetValue", &retryResult[T]{res, n})
+	})
+}
+
+// failed retries (after the backoff delay) the n-th attempt that failed
+// with error e, or fails the retry per the policy.
+func (r *retry[T]) failed(n int, e error) {
+	p := r.policy
+	if (p.Retryable != nil && !p.Retryable(e)) || (p.MaxAttempts > 0 && n >= p.MaxAttempts) {
+		r.fail(n, e)
+		return
+	}
+
+	var delay time.Duration
+	if p.Backoff != nil {
+		r.mu.Lock()
+		delay = p.Backoff(n, r.delay)
+		r.delay = delay
+		r.mu.Unlock()
+	}
+	if p.Deadline > 0 && time.Since(r.start)+delay >= p.Deadline {
+		r.fail(n, e) // next attempt would start past the deadline
+		return
+	}
+	if delay > 0 {
+		time.AfterFunc(delay, r.attempt)
+		return
+	}
+	go r.attempt()
+}
+
+// fail sets the error result of the retry after n attempts.
+func (r *retry[T]) fail(n int, e error) {
+	err := &RetryError{n, e}
+	r.g.complete("SetError", &retryResult[T]{&result[T]{e: err, isError: true}, n})
+}
+
+// expire fails the retry per the policy's deadline.
+func (r *retry[T]) expire() {
+	r.mu.Lock()
+	n := r.attempts
+	r.mu.Unlock()
+
+	r.fail(n, &TimeoutError{r.policy.Deadline})
+}
+
+// cancelPending cancels the future of the pending attempt (if any).
+func (r *retry[T]) cancelPending() {
+	r.mu.Lock()
+	f := r.pending
+	r.mu.Unlock()
+
+	if c, ok := f.(Canceller); ok {
+		c.Cancel(nil)
+	}
+}
+
+// done returns true if the retry is complete.
+func (r *retry[T]) done() bool {
+	select {
+	case <-r.g.Done():
+		return true
+	default:
+		return false
+	}
+}
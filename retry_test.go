@@ -0,0 +1,191 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flaky returns an attempt func that fails the first n attempts with err,
+// and then succeeds with value v.
+func flaky(n int32, err error, v interface{}) (func() Future, *int32) {
+	var calls int32
+	return func() Future {
+		i := atomic.AddInt32(&calls, 1)
+		return Async(func() (interface{}, error) {
+			if i <= n {
+				return nil, err
+			}
+			return v, nil
+		})
+	}, &calls
+}
+
+// attempts returns the number of attempts reported by the result r.
+func attempts(t *testing.T, r Result) int {
+	a, ok := r.(Attempted)
+	if !ok {
+		t.Fatal("expected result to support Attempted")
+	}
+	return a.Attempts()
+}
+
+// retry of an attempt that succeeds after failures
+// MUST have the successful result, and report the attempts made
+func TestRetrySuccess(t *testing.T) {
+	test := testSpec()
+	fn, calls := flaky(2, test.err, test.data)
+
+	f := Retry(RetryPolicy{MaxAttempts: 5, Backoff: ConstantBackoff(time.Millisecond)}, fn)
+	result, timeout := f.TryGet(time.Second)
+	if timeout || result.IsError() {
+		t.Fatalf("expected success - got %v", result)
+	}
+	if n := attempts(t, result); n != 3 || atomic.LoadInt32(calls) != 3 {
+		t.Errorf("expected 3 attempts - got %d", n)
+	}
+}
+
+// retry with attempts exhausted
+// MUST fail with a RetryError carrying the last error
+func TestRetryExhausted(t *testing.T) {
+	test := testSpec()
+	fn, calls := flaky(10, test.err, test.data)
+
+	f := Retry(RetryPolicy{MaxAttempts: 3}, fn)
+	result := f.Get()
+	var re *RetryError
+	if !errors.As(result.Error(), &re) || re.Attempts != 3 || re.Err != test.err {
+		t.Fatalf("expected RetryError after 3 attempts - got %v", result.Error())
+	}
+	if !errors.Is(result.Error(), test.err) {
+		t.Error("expected RetryError to wrap the last error")
+	}
+	if n := attempts(t, result); n != 3 || atomic.LoadInt32(calls) != 3 {
+		t.Errorf("expected 3 attempts - got %d", n)
+	}
+}
+
+// retry of an error that is not retryable
+// MUST fail after the first attempt
+func TestRetryNotRetryable(t *testing.T) {
+	test := testSpec()
+	fn, _ := flaky(10, test.err, test.data)
+
+	policy := RetryPolicy{Retryable: func(e error) bool { return !errors.Is(e, test.err) }}
+	result := Retry(policy, fn).Get()
+	if !errors.Is(result.Error(), test.err) || attempts(t, result) != 1 {
+		t.Errorf("expected spec error after 1 attempt - got %v", result.Error())
+	}
+
+	// panics & nil futures fail the attempt
+	var n int32
+	result = Retry(RetryPolicy{MaxAttempts: 2}, func() Future {
+		if atomic.AddInt32(&n, 1) == 1 {
+			panic(test.data)
+		}
+		return nil
+	}).Get()
+	var pe *PanicError
+	if !errors.Is(result.Error(), ErrNilValue) || attempts(t, result) != 2 {
+		t.Errorf("expected ErrNilValue after 2 attempts - got %v", result.Error())
+	}
+	if errors.As(result.Error(), &pe) {
+		t.Error("expected the last (not the first) error")
+	}
+}
+
+// retry per a zero policy, of an attempt that always fails
+// MUST fail after DefaultMaxAttempts attempts
+func TestRetryZeroPolicy(t *testing.T) {
+	test := testSpec()
+	fn, calls := flaky(1<<30, test.err, test.data)
+
+	result, timeout := Retry(RetryPolicy{}, fn).TryGet(time.Second)
+	var re *RetryError
+	switch {
+	case timeout:
+		t.Fatal("expected the retry to fail")
+	case !errors.As(result.Error(), &re) || re.Attempts != DefaultMaxAttempts:
+		t.Errorf("expected RetryError after %d attempts - got %v", DefaultMaxAttempts, result.Error())
+	case atomic.LoadInt32(calls) != DefaultMaxAttempts:
+		t.Errorf("expected %d attempts - got %d", DefaultMaxAttempts, atomic.LoadInt32(calls))
+	}
+}
+
+// retry exceeding its deadline
+// MUST fail with a TimeoutError, and cancel the pending attempt
+func TestRetryDeadline(t *testing.T) {
+	test := testSpec()
+
+	pending := make(chan *futureResult[interface{}], 1)
+	fn := func() Future {
+		f := NewUntypedFuture()
+		select {
+		case pending <- f:
+		default:
+		}
+		return f
+	}
+	result, timeout := Retry(RetryPolicy{Deadline: test.wait}, fn).TryGet(time.Second)
+	if timeout || !errors.Is(result.Error(), ErrTimeout) {
+		t.Fatalf("expected ErrTimeout - got %v", result)
+	}
+	// note: the pending attempt is cancelled once the retry has completed
+	f := <-pending
+	waitFor(t, "pending attempt cancelled", func() bool { return errors.Is(f.Err(), ErrCancelled) })
+
+	// backoff past the deadline fails with the last error
+	fn2, calls := flaky(10, test.err, test.data)
+	policy := RetryPolicy{Deadline: time.Second, Backoff: ConstantBackoff(time.Hour)}
+	result = Retry(policy, fn2).Get()
+	if !errors.Is(result.Error(), test.err) || atomic.LoadInt32(calls) != 1 {
+		t.Errorf("expected spec error after 1 attempt - got %v", result.Error())
+	}
+}
+
+// cancelled retry
+// MUST make no further attempts
+func TestRetryCancel(t *testing.T) {
+	test := testSpec()
+	fn, calls := flaky(100, test.err, test.data)
+
+	f := Retry(RetryPolicy{Backoff: ConstantBackoff(test.wait)}, fn)
+	f.(Canceller).Cancel(nil)
+	n := atomic.LoadInt32(calls)
+	time.Sleep(3 * test.wait)
+	if atomic.LoadInt32(calls) != n {
+		t.Error("expected no attempts after Cancel")
+	}
+}
+
+// backoff policies
+// MUST produce delays per their spec
+func TestBackoff(t *testing.T) {
+	if d := ConstantBackoff(time.Second)(5, time.Second); d != time.Second {
+		t.Errorf("constant: unexpected %s", d)
+	}
+
+	exp := ExponentialBackoff(time.Millisecond, 10*time.Millisecond)
+	for i, want := range []time.Duration{1, 2, 4, 8, 10, 10} {
+		if d := exp(i+1, 0); d != want*time.Millisecond {
+			t.Errorf("exponential %d: expected %s - got %s", i+1, want*time.Millisecond, d)
+		}
+	}
+	if d := ExponentialBackoff(time.Second, 0)(1000, 0); d <= 0 {
+		t.Errorf("exponential unbounded: overflow %s", d)
+	}
+
+	jitter := DecorrelatedJitterBackoff(time.Millisecond, 50*time.Millisecond)
+	var last time.Duration
+	for i := 1; i < 100; i++ {
+		d := jitter(i, last)
+		if d < time.Millisecond || d > 50*time.Millisecond || (last > 0 && d > 3*last) {
+			t.Fatalf("jitter %d: %s out of range (last %s)", i, d, last)
+		}
+		last = d
+	}
+}
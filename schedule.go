@@ -0,0 +1,360 @@
+package future
+
+import (
+	"container/heap"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ----------------------------------------------------------------------------
+// Timer Heap
+// ----------------------------------------------------------------------------
+
+// entry is a scheduled run in the timer heap.
+type entry struct {
+	at     time.Time       // due time
+	fire   func()          // dispatches the run - must not block
+	cancel func(err error) // cancels the run (on shutdown)
+	index  int             // heap index - -1 if not in heap
+}
+
+// timerHeap is a min-heap of entries ordered by due time.
+type timerHeap []*entry
+
+func (h timerHeap) Len() int           { return len(h) }
+func (h timerHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *timerHeap) Push(x interface{}) {
+	e := x.(*entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *timerHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*h = old[:len(old)-1]
+	return e
+}
+
+// ----------------------------------------------------------------------------
+// Scheduled Executor
+// ----------------------------------------------------------------------------
+
+// future.ScheduledExecutor runs tasks after a delay, or periodically, and
+// returns futures (or future.Periodic handles) for their results.
+//
+// All scheduled tasks are tracked by a single timer heap, serviced by a
+// single goroutine. Due tasks run on their own (transient) goroutine.
+//
+// A ScheduledExecutor is safe for concurrent use.
+type ScheduledExecutor struct {
+	mu       sync.Mutex // guards heap and closed
+	heap     timerHeap
+	closed   bool
+	wake     chan struct{} // signals a change of the earliest due time
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+// Creates a new ScheduledExecutor, and starts its timer goroutine.
+func NewScheduledExecutor() *ScheduledExecutor {
+	s := &ScheduledExecutor{
+		wake: make(chan struct{}, 1),
+		quit: make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+// Schedules the task to run after delay, returning a future for its result.
+// The result of the task is provided per future.Async. Each task runs on its
+// own goroutine, once due: tasks that are due together run concurrently, in
+// no particular order.
+//
+// Cancelling the returned future (per future.Canceller) before the task
+// runs unschedules the task. After Shutdown the returned future is set with
+// an error that errors.Is future.ErrShutdown.
+func (s *ScheduledExecutor) Schedule(delay time.Duration, task func() (interface{}, error)) Future {
+	f := NewUntypedFuture()
+	e := &entry{
+		fire: func() {
+			go func() {
+				if f.Err() == nil {
+					provide[interface{}](f)(try(task))
+				}
+			}()
+		},
+		cancel: func(err error) { f.Cancel(err) },
+		index:  -1,
+	}
+	if !s.push(e, time.Now().Add(delay)) {
+		f.SetError(&StateError{"Schedule", ErrShutdown})
+		return f
+	}
+	f.OnFailure(func(error) {
+		if f.Err() != nil {
+			s.remove(e)
+		}
+	})
+	return f
+}
+
+// Schedules the task to run periodically, first after initialDelay, and then
+// every period thereafter. Runs never overlap: a run that is due while the
+// previous run is still running is skipped (see future.Periodic#Skipped).
+// The period must be greater than zero; if not, ScheduleAtFixedRate panics.
+func (s *ScheduledExecutor) ScheduleAtFixedRate(initialDelay, period time.Duration, task func() (interface{}, error)) *Periodic {
+	if period <= 0 {
+		panic("future: non-positive period for ScheduleAtFixedRate")
+	}
+	return s.schedulePeriodic(initialDelay, period, true, task)
+}
+
+// Schedules the task to run periodically, first after initialDelay, and then
+// with the given delay between the end of a run and the start of the next.
+// The delay must be greater than zero; if not, ScheduleWithFixedDelay panics.
+func (s *ScheduledExecutor) ScheduleWithFixedDelay(initialDelay, delay time.Duration, task func() (interface{}, error)) *Periodic {
+	if delay <= 0 {
+		panic("future: non-positive delay for ScheduleWithFixedDelay")
+	}
+	return s.schedulePeriodic(initialDelay, delay, false, task)
+}
+
+// Shutdown stops the executor. Tasks that are not yet running are cancelled,
+// with an error that errors.Is future.ErrCancelled (and future.ErrShutdown).
+// Running tasks are not interrupted.
+func (s *ScheduledExecutor) Shutdown() {
+	s.quitOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		pending := s.heap
+		s.heap = nil
+		for _, e := range pending {
+			e.index = -1
+		}
+		s.mu.Unlock()
+
+		close(s.quit)
+		for _, e := range pending {
+			e.cancel(ErrShutdown)
+		}
+	})
+}
+
+// push adds e to the heap, due at time at. Returns false if the executor
+// is shut down.
+func (s *ScheduledExecutor) push(e *entry, at time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	e.at = at
+	heap.Push(&s.heap, e)
+	if e.index == 0 {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// remove removes e from the heap, if present.
+func (s *ScheduledExecutor) remove(e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.index >= 0 {
+		heap.Remove(&s.heap, e.index)
+	}
+}
+
+// loop fires due entries, and sleeps until the next is due.
+func (s *ScheduledExecutor) loop() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	var due []*entry
+	for {
+		now := time.Now()
+		wait := time.Hour
+
+		s.mu.Lock()
+		for len(s.heap) > 0 && !s.heap[0].at.After(now) {
+			due = append(due, heap.Pop(&s.heap).(*entry))
+		}
+		if len(s.heap) > 0 {
+			wait = s.heap[0].at.Sub(now)
+		}
+		s.mu.Unlock()
+
+		for i, e := range due {
+			e.fire()
+			due[i] = nil
+		}
+		due = due[:0]
+
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------
+// Periodic
+// ----------------------------------------------------------------------------
+
+// future.Periodic is the handle of a periodic task. Each run of the task
+// publishes its result (value or error) via the future returned by Next.
+//
+// A Periodic is safe for concurrent use.
+type Periodic struct {
+	s         *ScheduledExecutor
+	task      func() (interface{}, error)
+	period    time.Duration
+	fixedRate bool
+	entry     *entry
+	running   atomic.Bool // a run is in progress
+	runs      atomic.Uint64
+	skipped   atomic.Uint64
+
+	mu        sync.Mutex // guards next and cancelled
+	next      *futureResult[interface{}]
+	cancelled bool
+}
+
+// schedulePeriodic creates and schedules a periodic task.
+func (s *ScheduledExecutor) schedulePeriodic(initialDelay, period time.Duration, fixedRate bool, task func() (interface{}, error)) *Periodic {
+	p := &Periodic{
+		s:         s,
+		task:      task,
+		period:    period,
+		fixedRate: fixedRate,
+		next:      NewUntypedFuture(Broadcast()),
+	}
+	p.entry = &entry{
+		fire:   p.fire,
+		cancel: func(err error) { p.cancel(err) },
+		index:  -1,
+	}
+	p.reschedule(time.Now().Add(initialDelay))
+	return p
+}
+
+// Returns a (broadcast) future for the result of the next run. If the task
+// is cancelled before the next run, the future is cancelled.
+func (p *Periodic) Next() Future {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.next
+}
+
+// Cancels the periodic task: no further runs are scheduled, and the future
+// of the next run is cancelled. A run in progress is not interrupted, but its
+// result is not published. Returns false if already cancelled.
+func (p *Periodic) Cancel() bool {
+	ok := p.cancel(nil)
+	p.s.remove(p.entry)
+	return ok
+}
+
+// Returns the number of completed runs.
+func (p *Periodic) Runs() uint64 {
+	return p.runs.Load()
+}
+
+// Returns the number of (fixed rate) runs skipped as the previous run
+// was still in progress when due.
+func (p *Periodic) Skipped() uint64 {
+	return p.skipped.Load()
+}
+
+// cancel marks the task cancelled, and cancels the next run's future.
+func (p *Periodic) cancel(cause error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.cancelLocked(cause)
+}
+
+// cancelLocked is cancel, with p.mu held by the caller.
+func (p *Periodic) cancelLocked(cause error) bool {
+	if p.cancelled {
+		return false
+	}
+	p.cancelled = true
+	p.next.Cancel(cause)
+	return true
+}
+
+// fire runs the task (on a new goroutine) unless the previous run is still
+// in progress, and, for fixed rate tasks, schedules the next run.
+func (p *Periodic) fire() {
+	due := p.entry.at
+	if !p.running.CompareAndSwap(false, true) {
+		p.skipped.Add(1)
+	} else {
+		go p.run()
+	}
+	if p.fixedRate {
+		p.reschedule(due.Add(p.period))
+	}
+}
+
+// run runs the task, publishes its result, and, for fixed delay tasks,
+// schedules the next run.
+func (p *Periodic) run() {
+	v, e := try(p.task)
+	p.runs.Add(1)
+
+	p.mu.Lock()
+	f := p.next
+	if !p.cancelled {
+		p.next = NewUntypedFuture(Broadcast())
+	}
+	p.mu.Unlock()
+
+	p.running.Store(false)
+	provide[interface{}](f)(v, e)
+
+	if !p.fixedRate {
+		p.reschedule(time.Now().Add(p.period))
+	}
+}
+
+// reschedule pushes the entry for the next run at time at, unless the
+// task is cancelled. (p.mu is held so that Cancel either observes the
+// pushed entry, or reschedule observes the cancellation.)
+func (p *Periodic) reschedule(at time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancelled {
+		return
+	}
+	if !p.s.push(p.entry, at) {
+		p.cancelLocked(ErrShutdown)
+	}
+}
@@ -0,0 +1,204 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tasks scheduled out of order
+// MUST each run, no earlier than their delay
+// note: tasks due together (e.g. per a late wake-up) run in no particular
+// order, so the order of the runs is not asserted
+func TestScheduleOrder(t *testing.T) {
+	s := NewScheduledExecutor()
+	defer s.Shutdown()
+
+	t0 := time.Now()
+	delays := []time.Duration{30, 10, 20}
+	futures := make([]Future, len(delays))
+	for i, d := range delays {
+		i, d := i, d*time.Millisecond
+		futures[i] = s.Schedule(d, func() (interface{}, error) {
+			if elapsed := time.Since(t0); elapsed < d {
+				t.Errorf("task due after %s ran after %s", d, elapsed)
+			}
+			return i, nil
+		})
+	}
+	for i := range delays {
+		if result, timeout := futures[i].TryGet(time.Second); timeout || result.Value() != i {
+			t.Errorf("task %d: expected to run - got %v", i, result)
+		}
+	}
+
+	test := testSpec()
+	f := s.Schedule(0, func() (interface{}, error) { return nil, test.err })
+	if result := f.Get(); result.Error() != test.err {
+		t.Errorf("expected spec error - got %v", result.Error())
+	}
+}
+
+// scheduled task cancelled before it is due
+// MUST not run
+func TestScheduleCancel(t *testing.T) {
+	s := NewScheduledExecutor()
+	defer s.Shutdown()
+
+	var ran atomic.Bool
+	f := s.Schedule(20*time.Millisecond, func() (interface{}, error) {
+		ran.Store(true)
+		return nil, nil
+	})
+	if !f.(Canceller).Cancel(nil) {
+		t.Fatal("expected Cancel to succeed")
+	}
+	if result := f.Get(); !errors.Is(result.Error(), ErrCancelled) {
+		t.Errorf("expected ErrCancelled - got %v", result.Error())
+	}
+	time.Sleep(40 * time.Millisecond)
+	if ran.Load() {
+		t.Error("cancelled task ran")
+	}
+
+	s.mu.Lock()
+	n := len(s.heap)
+	s.mu.Unlock()
+	if n != 0 {
+		t.Errorf("expected cancelled task removed from heap - got %d entries", n)
+	}
+}
+
+// fixed rate task slower than its period
+// MUST skip overlapping runs, and publish each run's result via Next
+func TestScheduleFixedRate(t *testing.T) {
+	s := NewScheduledExecutor()
+	defer s.Shutdown()
+
+	var n int32
+	p := s.ScheduleAtFixedRate(0, 5*time.Millisecond, func() (interface{}, error) {
+		time.Sleep(12 * time.Millisecond)
+		return atomic.AddInt32(&n, 1), nil
+	})
+	for i := int32(1); i <= 3; i++ {
+		result, timeout := p.Next().TryGet(time.Second)
+		if timeout || result.Value().(int32) < i {
+			t.Fatalf("run %d: unexpected result %v", i, result)
+		}
+	}
+	p.Cancel()
+	if p.Skipped() == 0 {
+		t.Error("expected overlapping runs to be skipped")
+	}
+	if p.Runs() < 3 {
+		t.Errorf("expected at least 3 runs - got %d", p.Runs())
+	}
+}
+
+// fixed delay task
+// MUST keep the delay between the end of a run and the start of the next
+func TestScheduleFixedDelay(t *testing.T) {
+	s := NewScheduledExecutor()
+	defer s.Shutdown()
+
+	const delay = 10 * time.Millisecond
+	var last atomic.Int64
+	var short atomic.Bool
+	test := testSpec()
+	p := s.ScheduleWithFixedDelay(0, delay, func() (interface{}, error) {
+		if end := last.Load(); end != 0 && time.Since(time.Unix(0, end)) < delay {
+			short.Store(true)
+		}
+		time.Sleep(5 * time.Millisecond)
+		last.Store(time.Now().UnixNano())
+		return nil, test.err
+	})
+	for i := 0; i < 3; i++ {
+		if result, timeout := p.Next().TryGet(time.Second); timeout || result.Error() != test.err {
+			t.Fatalf("run %d: expected spec error - got %v", i, result)
+		}
+	}
+	p.Cancel()
+	if short.Load() {
+		t.Error("run started before delay elapsed")
+	}
+	if p.Skipped() != 0 {
+		t.Errorf("expected no skipped runs - got %d", p.Skipped())
+	}
+}
+
+// periodic task cancelled
+// MUST cancel the future of its next run, and not run again
+func TestSchedulePeriodicCancel(t *testing.T) {
+	s := NewScheduledExecutor()
+	defer s.Shutdown()
+
+	p := s.ScheduleAtFixedRate(time.Hour, time.Hour, func() (interface{}, error) { return nil, nil })
+	next := p.Next()
+	if !p.Cancel() {
+		t.Fatal("expected Cancel to succeed")
+	}
+	if p.Cancel() {
+		t.Error("expected second Cancel to fail")
+	}
+	if result, timeout := next.TryGet(time.Second); timeout || !errors.Is(result.Error(), ErrCancelled) {
+		t.Errorf("expected ErrCancelled - got %v", result)
+	}
+}
+
+// periodic task with a non-positive period (or delay)
+// MUST panic, per time.NewTicker
+func TestSchedulePeriodicNonPositive(t *testing.T) {
+	s := NewScheduledExecutor()
+	defer s.Shutdown()
+
+	task := func() (interface{}, error) { return nil, nil }
+	for _, tc := range []struct {
+		name     string
+		schedule func()
+	}{
+		{"fixed rate zero", func() { s.ScheduleAtFixedRate(0, 0, task) }},
+		{"fixed rate negative", func() { s.ScheduleAtFixedRate(0, -time.Second, task) }},
+		{"fixed delay zero", func() { s.ScheduleWithFixedDelay(0, 0, task) }},
+		{"fixed delay negative", func() { s.ScheduleWithFixedDelay(0, -time.Second, task) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", tc.name)
+				}
+			}()
+			tc.schedule()
+		}()
+	}
+}
+
+// Shutdown with tasks pending
+// MUST cancel pending tasks with ErrShutdown, and reject new ones
+func TestScheduleShutdown(t *testing.T) {
+	s := NewScheduledExecutor()
+
+	f := s.Schedule(time.Hour, func() (interface{}, error) { return nil, nil })
+	p := s.ScheduleWithFixedDelay(time.Hour, time.Hour, func() (interface{}, error) { return nil, nil })
+	s.Shutdown()
+	s.Shutdown() // idempotent
+
+	for i, g := range []Future{f, p.Next()} {
+		result, timeout := g.TryGet(time.Second)
+		if timeout || !errors.Is(result.Error(), ErrCancelled) || !errors.Is(result.Error(), ErrShutdown) {
+			t.Errorf("%d: expected ErrCancelled & ErrShutdown - got %v", i, result)
+		}
+	}
+
+	g := s.Schedule(0, func() (interface{}, error) { return nil, nil })
+	if result := g.Get(); !errors.Is(result.Error(), ErrShutdown) {
+		t.Errorf("expected ErrShutdown - got %v", result.Error())
+	}
+	q := s.ScheduleAtFixedRate(0, time.Millisecond, func() (interface{}, error) { return nil, nil })
+	if result := q.Next().Get(); !errors.Is(result.Error(), ErrShutdown) {
+		t.Errorf("periodic: expected ErrShutdown - got %v", result.Error())
+	}
+}
@@ -0,0 +1,60 @@
+package future
+
+// ----------------------------------------------------------------------------
+// Async Service
+// ----------------------------------------------------------------------------
+
+// future.Handler handles a request of a future.AsyncService, returning its
+// response (or error).
+type Handler[Req, Resp any] func(req Req) (Resp, error)
+
+// future.Middleware wraps a handler of a future.AsyncService - e.g. for
+// logging, metrics, or auth - returning a handler that (typically) calls
+// next.
+type Middleware[Req, Resp any] func(next Handler[Req, Resp]) Handler[Req, Resp]
+
+// future.AsyncService is an asynchronous request/response service: requests
+// are queued per Call, and handled by a fixed number of worker goroutines,
+// with the (value, error) response provided via the future returned by Call.
+//
+// The service is a future.Executor of its handler: see future.Execute for
+// the semantics of saturation, panics, and cancellation of queued requests.
+//
+// An AsyncService is safe for concurrent use.
+type AsyncService[Req, Resp any] struct {
+	executor *Executor
+	handler  Handler[Req, Resp]
+}
+
+// Creates a new AsyncService, and starts its workers. Requests are queued
+// (up to queueSize) per the saturation policy, and are handled by the handler
+// wrapped in the middleware: the first middleware is the outermost.
+func NewAsyncService[Req, Resp any](workers, queueSize int, policy SaturationPolicy, handler Handler[Req, Resp], middleware ...Middleware[Req, Resp]) *AsyncService[Req, Resp] {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return &AsyncService[Req, Resp]{
+		executor: NewExecutor(workers, queueSize, policy),
+		handler:  handler,
+	}
+}
+
+// Queues the request, returning a future for its response. If the request
+// can not be queued, the returned future is set with an error that
+// errors.Is future.ErrRejected or future.ErrShutdown.
+func (s *AsyncService[Req, Resp]) Call(req Req) TypedFuture[Resp] {
+	return Execute(s.executor, func() (Resp, error) { return s.handler(req) })
+}
+
+// Stop stops accepting requests, and waits until all queued (and in
+// progress) requests are handled.
+func (s *AsyncService[Req, Resp]) Stop() {
+	s.executor.Shutdown()
+}
+
+// StopNow stops accepting requests, fails queued requests with an error
+// that errors.Is future.ErrShutdown, and waits until in progress requests
+// are handled.
+func (s *AsyncService[Req, Resp]) StopNow() {
+	s.executor.ShutdownNow()
+}
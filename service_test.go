@@ -0,0 +1,169 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// requests to a service
+// MUST be handled, with responses provided via their futures
+func TestServiceCall(t *testing.T) {
+	test := testSpec()
+	s := NewAsyncService(4, 16, Block, func(req int) (int, error) {
+		if req < 0 {
+			return 0, test.err
+		}
+		return req * 2, nil
+	})
+	defer s.Stop()
+
+	futures := make([]TypedFuture[int], 50)
+	for i := range futures {
+		futures[i] = s.Call(i)
+	}
+	for i, f := range futures {
+		if result, timeout := f.TryGet(time.Second); timeout || result.Value() != i*2 {
+			t.Fatalf("%d: unexpected result %v", i, result)
+		}
+	}
+	if result := s.Call(-1).Get(); result.Error() != test.err {
+		t.Errorf("expected spec error - got %v", result.Error())
+	}
+}
+
+// middleware chain
+// MUST wrap the handler in order, first outermost
+func TestServiceMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var trace []string
+	record := func(s string) {
+		mu.Lock()
+		trace = append(trace, s)
+		mu.Unlock()
+	}
+
+	var calls atomic.Int32
+	metrics := func(next Handler[string, string]) Handler[string, string] {
+		return func(req string) (string, error) {
+			calls.Add(1)
+			record("metrics")
+			return next(req)
+		}
+	}
+	errDenied := errors.New("denied")
+	auth := func(next Handler[string, string]) Handler[string, string] {
+		return func(req string) (string, error) {
+			record("auth")
+			if !strings.HasPrefix(req, "user:") {
+				return "", errDenied
+			}
+			return next(req)
+		}
+	}
+	handler := func(req string) (string, error) {
+		record("handler")
+		return strings.ToUpper(req), nil
+	}
+
+	s := NewAsyncService(1, 1, Block, handler, metrics, auth)
+	defer s.Stop()
+
+	if result := s.Call("user:x").Get(); result.Value() != "USER:X" {
+		t.Fatalf("unexpected result %v", result)
+	}
+	if result := s.Call("x").Get(); result.Error() != errDenied {
+		t.Errorf("expected denied - got %v", result.Error())
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls - got %d", calls.Load())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(trace, ","); got != "metrics,auth,handler,metrics,auth" {
+		t.Errorf("unexpected middleware order %s", got)
+	}
+}
+
+// saturated service with Reject policy
+// MUST reject requests with ErrRejected
+func TestServiceReject(t *testing.T) {
+	release := make(chan struct{})
+	s := NewAsyncService(1, 1, Reject, func(req int) (int, error) {
+		<-release
+		return req, nil
+	})
+
+	started := s.Call(1)
+	waitFor(t, "handling", func() bool { return len(s.executor.queue) == 0 })
+	queued := s.Call(2)
+	if result := s.Call(3).Get(); !errors.Is(result.Error(), ErrRejected) {
+		t.Errorf("expected ErrRejected - got %v", result.Error())
+	}
+	close(release)
+	s.Stop()
+
+	for i, f := range []TypedFuture[int]{started, queued} {
+		if result := f.Get(); result.Value() != i+1 {
+			t.Errorf("%d: unexpected result %v", i+1, result)
+		}
+	}
+}
+
+// stopped service
+// MUST handle queued requests (per Stop), or fail them (per StopNow)
+func TestServiceStop(t *testing.T) {
+	for _, now := range []bool{false, true} {
+		started := make(chan struct{}, 3)
+		release := make(chan struct{})
+		s := NewAsyncService(1, 4, Block, func(req int) (int, error) {
+			started <- struct{}{}
+			<-release
+			return req, nil
+		})
+		futures := []TypedFuture[int]{s.Call(1), s.Call(2), s.Call(3)}
+		<-started // request 1 in progress - 2 & 3 queued
+
+		// stop, and only then release the request in progress
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			if now {
+				s.StopNow()
+			} else {
+				s.Stop()
+			}
+		}()
+		waitFor(t, "stop", func() bool {
+			select {
+			case <-s.executor.quit:
+				return true
+			default:
+				return false
+			}
+		})
+		close(release)
+		<-stopped
+
+		for i, f := range futures {
+			result, timeout := f.TryGet(0)
+			switch {
+			case timeout:
+				t.Fatalf("now=%v %d: expected result after stop", now, i+1)
+			case now && i > 0 && !errors.Is(result.Error(), ErrShutdown):
+				t.Errorf("now=%v %d: expected ErrShutdown - got %v", now, i+1, result)
+			case (!now || i == 0) && result.Value() != i+1:
+				t.Errorf("now=%v %d: unexpected result %v", now, i+1, result)
+			}
+		}
+		if result := s.Call(4).Get(); !errors.Is(result.Error(), ErrShutdown) {
+			t.Errorf("now=%v: expected ErrShutdown after stop - got %v", now, result.Error())
+		}
+	}
+}
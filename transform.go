@@ -0,0 +1,150 @@
+package future
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+/* Transformations of futures - each returns a new future */
+
+// ----------------------------------------------------------------------------
+// Panics
+// ----------------------------------------------------------------------------
+
+// future.PanicError is the error result of a future whose user function
+// panicked.
+type PanicError struct {
+	Value interface{} // the recovered panic value
+	Stack []byte      // stack trace of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered panic: %v", e.Value)
+}
+
+// Returns the panic value if it is an error, and otherwise nil.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// try calls fn, converting a panic into a *PanicError error.
+func try[U any](fn func() (U, error)) (v U, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = &PanicError{Value: p, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
+
+// ----------------------------------------------------------------------------
+// Combinators
+// ----------------------------------------------------------------------------
+
+// future.Then returns a future for the result of fn applied to the value of
+// future f. The error result of f, or the non-nil error of fn, is the error
+// result of the returned future.
+//
+// If f is a future.TypedCallbackFuture, no goroutine is dedicated to
+// waiting on f, and its result is not consumed: fn runs on the goroutine
+// that completes f (see future.TypedCallbackFuture), and must not block.
+// Otherwise the result of f is obtained via Get, and f must not be consumed
+// elsewhere.
+func Then[T, U any](f TypedFuture[T], fn func(v T) (U, error)) TypedFuture[U] {
+	g := NewFuture[U]()
+	whenComplete(f, func(r TypedResult[T]) {
+		if r.IsError() {
+			g.SetError(r.Error())
+			return
+		}
+		provide[U](g)(try(func() (U, error) { return fn(r.Value()) }))
+	})
+	return g
+}
+
+// future.Map returns a future for the result of fn applied to the value of
+// future f. The error result of f is the error result of the returned future.
+// See future.Then.
+func Map[T, U any](f TypedFuture[T], fn func(v T) U) TypedFuture[U] {
+	return Then(f, func(v T) (U, error) { return fn(v), nil })
+}
+
+// future.FlatMap returns a future for the result of the future returned by fn
+// applied to the value of future f. The error result of f is the error result
+// of the returned future. See future.Then.
+func FlatMap[T, U any](f TypedFuture[T], fn func(v T) TypedFuture[U]) TypedFuture[U] {
+	g := NewFuture[U]()
+	whenComplete(f, func(r TypedResult[T]) {
+		if r.IsError() {
+			g.SetError(r.Error())
+			return
+		}
+		h, e := try(func() (TypedFuture[U], error) { return fn(r.Value()), nil })
+		if e == nil && h == nil {
+			e = &StateError{"FlatMap", ErrNilValue}
+		}
+		if e != nil {
+			g.SetError(e)
+			return
+		}
+		whenComplete(h, func(r TypedResult[U]) {
+			provide[U](g)(r.Value(), r.Error())
+		})
+	})
+	return g
+}
+
+// future.Catch returns a future for the result of fn applied to the error
+// result of future f. The value result of f is the value result of the
+// returned future. fn may recover with a value, or return an error (e.g. the
+// given error, if it is not recoverable). See future.Then.
+func Catch[T any](f TypedFuture[T], fn func(e error) (T, error)) TypedFuture[T] {
+	g := NewFuture[T]()
+	whenComplete(f, func(r TypedResult[T]) {
+		if !r.IsError() {
+			provide[T](g)(r.Value(), nil)
+			return
+		}
+		provide[T](g)(try(func() (T, error) { return fn(r.Error()) }))
+	})
+	return g
+}
+
+// future.Recover returns a future for the value returned by fn applied to the
+// error result of future f. The value result of f is the value result of the
+// returned future. See future.Catch.
+func Recover[T any](f TypedFuture[T], fn func(e error) T) TypedFuture[T] {
+	return Catch(f, func(e error) (T, error) { return fn(e), nil })
+}
+
+// ----------------------------------------------------------------------------
+// support
+// ----------------------------------------------------------------------------
+
+// whenComplete calls fn with the result of future f once it is available,
+// via future.TypedCallbackFuture#OnComplete if supported, and otherwise via
+// a goroutine blocked in f.Get.
+func whenComplete[T any](f TypedFuture[T], fn func(r TypedResult[T])) {
+	if cf, ok := f.(TypedCallbackFuture[T]); ok {
+		cf.OnComplete(fn)
+		return
+	}
+	go func() { fn(f.Get()) }()
+}
+
+// provide returns a func that sets either the value or the error
+// result of p, per the (value, error) return pair of a user function.
+// A nil value rejected by p is provided as an ErrNilValue error result.
+func provide[T any](p TypedProvider[T]) func(v T, e error) {
+	return func(v T, e error) {
+		if e != nil {
+			p.SetError(e)
+			return
+		}
+		if e := p.SetValue(v); errors.Is(e, ErrNilValue) {
+			p.SetError(e)
+		}
+	}
+}
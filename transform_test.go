@@ -0,0 +1,131 @@
+/* white box tests */
+
+package future
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// ____________________________________________________________________
+// combinators
+
+// Map of a value result
+// MUST apply fn to the value
+func TestMapValue(t *testing.T) {
+	f := NewFuture[int]()
+	g := Map[int, string](f, strconv.Itoa)
+
+	f.SetValue(42)
+
+	result, timeout := g.TryGet(time.Second)
+	switch {
+	case timeout:
+		t.Fatal("expected result by now")
+	case result.IsError():
+		t.Errorf("unexpected error %s", result.Error())
+	case result.Value() != "42":
+		t.Errorf("unexpected value %q", result.Value())
+	}
+}
+
+// Map | Then | FlatMap of an error result
+// MUST pass through the error unchanged
+// MUST NOT call fn
+func TestMapErrorPassThrough(t *testing.T) {
+	test := testSpec()
+
+	f := NewFuture[int](Broadcast())
+	fn := func(v int) string { t.Error("unexpected call to fn"); return "" }
+	futures := []TypedFuture[string]{
+		Map(f, fn),
+		Then(f, func(v int) (string, error) { return fn(v), nil }),
+		FlatMap(f, func(v int) TypedFuture[string] { fn(v); return nil }),
+	}
+
+	f.SetError(test.err)
+
+	for i, g := range futures {
+		if result := g.Get(); result.Error() != test.err {
+			t.Errorf("%d: expected spec error - got %v", i, result.Error())
+		}
+	}
+}
+
+// Then with a panicking fn
+// MUST result in a *PanicError error result
+func TestThenPanic(t *testing.T) {
+	f := NewFuture[int]()
+	g := Then(f, func(v int) (int, error) { return 100 / v, nil })
+
+	f.SetValue(0)
+
+	var pe *PanicError
+	result := g.Get()
+	switch {
+	case !result.IsError():
+		t.Fatal("expected error result")
+	case !errors.As(result.Error(), &pe):
+		t.Fatalf("expected *PanicError - got %v", result.Error())
+	case len(pe.Stack) == 0:
+		t.Error("expected panic stack trace")
+	}
+}
+
+// FlatMap of a value result
+// MUST result in the result of the future returned by fn
+func TestFlatMapValue(t *testing.T) {
+	test := testSpec()
+
+	f := NewFuture[int]()
+	g := FlatMap(f, func(v int) TypedFuture[int] {
+		h := NewFuture[int]()
+		go func() {
+			time.Sleep(test.providerDelay)
+			h.SetValue(v * 2)
+		}()
+		return h
+	})
+
+	f.SetValue(21)
+
+	if result, timeout := g.TryGet(time.Second); timeout || result.Value() != 42 {
+		t.Error("expected value 42")
+	}
+}
+
+// Recover | Catch of an error result
+// MUST apply fn to the error
+// Catch MAY rethrow the error
+func TestRecoverAndCatch(t *testing.T) {
+	test := testSpec()
+
+	f := NewUntypedFuture(Broadcast())
+	recovered := Recover(f, func(e error) interface{} { return "fallback" })
+	rethrown := Catch(f, func(e error) (interface{}, error) { return nil, e })
+
+	f.SetError(test.err)
+
+	if result := recovered.Get(); result.IsError() || result.Value() != "fallback" {
+		t.Error("expected recovered value")
+	}
+	if result := rethrown.Get(); result.Error() != test.err {
+		t.Errorf("expected rethrown spec error - got %v", result.Error())
+	}
+}
+
+// Map of a future that does not support callbacks
+// MUST obtain the result via Get
+func TestMapForeignFuture(t *testing.T) {
+	f := NewFuture[int]()
+	var foreign TypedFuture[int] = struct{ TypedFuture[int] }{f}
+
+	g := Map(foreign, func(v int) int { return v + 1 })
+	f.SetValue(1)
+
+	if result, timeout := g.TryGet(time.Second); timeout || result.Value() != 2 {
+		t.Error("expected value 2")
+	}
+}
@@ -1,27 +1,31 @@
 package future
 
 import (
-	"errors"
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-/* The Untyped implementation of Future (api) */
+/* The reference implementation of Future (api) - untyped and typed */
 
 // ----------------------------------------------------------------------------
 // Result Value
 // ----------------------------------------------------------------------------
 
-// untyped supports ~generic future.Result interface.
+// result supports the future.TypedResult interface.
 //
-// wraps an untyped (interface{}) value reference that
-// is either the future result value or an error.
-type result struct {
-	v       interface{} // value ref. w/ modal semantics
-	isError bool        // determines v value semantics
+// wraps a value reference of type T, or an error, that
+// is the future result.
+type result[T any] struct {
+	v       T     // value ref. - zero-value if isError
+	e       error // error ref. - nil unless isError
+	isError bool  // determines result semantics
 }
 
 // interface: future.Result#Value()
-func (r *result) Value() (v interface{}) {
+func (r *result[T]) Value() (v T) {
 	if !r.isError {
 		v = r.v
 	}
@@ -29,15 +33,15 @@ func (r *result) Value() (v interface{}) {
 }
 
 // interface: future.Result#Error()
-func (r *result) Error() (err error) {
+func (r *result[T]) Error() (err error) {
 	if r.isError {
-		err = r.v.(error)
+		err = r.e
 	}
 	return
 }
 
 // interface: future.Result#Error()
-func (r *result) IsError() bool {
+func (r *result[T]) IsError() bool {
 	return r.isError
 }
 
@@ -45,18 +49,38 @@ func (r *result) IsError() bool {
 // Future Object
 // ----------------------------------------------------------------------------
 
-// future.futureResult supports future.Future and future.Provider
+// future.futureResult supports future.TypedFuture and future.TypedProvider
 // Instances of this object are created by the future.Result provider,
 // and returned to the call site as future.Future references.
-type futureResult struct {
-	rchan     chan Result
-	finalized bool // prevent multiple sets
+type futureResult[T any] struct {
+	mu        sync.Mutex             // guards finalized, err, and callbacks
+	done      chan struct{}          // closed on set - replaced on Reset
+	r         TypedResult[T]         // the result - valid once done is closed
+	broadcast bool                   // see future.Broadcast
+	nilable   bool                   // nil values of T are rejected
+	consumed  atomic.Bool            // one-shot result handed off
+	finalized bool                   // prevent multiple sets
+	cancelled chan struct{}          // closed on Cancel - lazily created
+	err       error                  // cancellation error
+	callbacks []func(TypedResult[T]) // pending completion callbacks
+	notifying bool                   // callbacks are being run
+	waiting   atomic.Int32           // consumers in Get, TryGet, etc.
+	pooled    atomic.Bool            // recycled - see future.Pool
+	gen       atomic.Uint64          // generation - bumped per recycle
 }
 
 // Creates a new untyped Future object.
-func NewUntypedFuture() *futureResult {
-	return &futureResult{
-		rchan:     make(chan Result, 1),
+func NewUntypedFuture(opts ...Option) *futureResult[interface{}] {
+	return NewFuture[interface{}](opts...)
+}
+
+// Creates a new type-safe Future object for values of type T.
+func NewFuture[T any](opts ...Option) *futureResult[T] {
+	o := newOptions(opts)
+	return &futureResult[T]{
+		done:      make(chan struct{}),
+		broadcast: o.broadcast,
+		nilable:   !o.allowNil && nilable[T](),
 		finalized: false,
 	}
 }
@@ -65,42 +89,475 @@ func NewUntypedFuture() *futureResult {
 // support for future.Future
 
 // interface: future.Future#Get
-func (p *futureResult) Get() (r Result) {
-	r = <-(p.rchan)
-	return
+func (p *futureResult[T]) Get() (r TypedResult[T]) {
+	return p.getGen(p.gen.Load())
+}
+
+// getGen is Get of generation gen. see future.PooledFuture.
+func (p *futureResult[T]) getGen(gen uint64) (r TypedResult[T]) {
+	if !p.enter(gen) {
+		return recycled[T]("Get")
+	}
+	defer p.exit()
+
+	<-(p.done)
+	return p.result("Get")
 }
 
 // interface: future.Future#TryGet
-func (p *futureResult) TryGet(ns time.Duration) (r Result, timeout bool) {
+func (p *futureResult[T]) TryGet(ns time.Duration) (r TypedResult[T], timeout bool) {
+	return p.tryGetGen(p.gen.Load(), ns)
+}
+
+// tryGetGen is TryGet of generation gen.
+func (p *futureResult[T]) tryGetGen(gen uint64, ns time.Duration) (r TypedResult[T], timeout bool) {
+	if !p.enter(gen) {
+		return recycled[T]("TryGet"), false
+	}
+	defer p.exit()
+
+	// fast path - result is already available
+	select {
+	case <-(p.done):
+		return p.result("TryGet"), false
+	default:
+	}
+	if ns <= 0 {
+		return nil, true
+	}
+
+	timer := getTimer(ns)
 	select {
-	case r = <-(p.rchan):
-	case <-time.After(ns):
+	case <-(p.done):
+		r = p.result("TryGet")
+	case <-timer.C:
 		timeout = true
 	}
+	putTimer(timer)
 	return
 }
 
+// interface: future.ContextFuture#GetContext
+func (p *futureResult[T]) GetContext(ctx context.Context) (r TypedResult[T], err error) {
+	return p.getContextGen(p.gen.Load(), ctx)
+}
+
+// getContextGen is GetContext of generation gen.
+func (p *futureResult[T]) getContextGen(gen uint64, ctx context.Context) (r TypedResult[T], err error) {
+	if !p.enter(gen) {
+		return recycled[T]("GetContext"), nil
+	}
+	defer p.exit()
+
+	select {
+	case <-(p.done):
+		r = p.result("GetContext")
+	case <-ctx.Done():
+		err = context.Cause(ctx)
+	}
+	return
+}
+
+// interface: future.SelectableFuture#Done
+func (p *futureResult[T]) Done() <-chan struct{} {
+	return p.doneGen(p.gen.Load())
+}
+
+// doneGen is Done of generation gen. The Done channel of a recycled future
+// is closed (see Poll).
+func (p *futureResult[T]) doneGen(gen uint64) <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stale(gen) {
+		return closedChan
+	}
+	return p.done
+}
+
+// interface: future.SelectableFuture#Poll
+func (p *futureResult[T]) Poll() (r TypedResult[T], ok bool) {
+	return p.pollGen(p.gen.Load())
+}
+
+// pollGen is Poll of generation gen.
+func (p *futureResult[T]) pollGen(gen uint64) (r TypedResult[T], ok bool) {
+	if !p.enter(gen) {
+		return recycled[T]("Poll"), true
+	}
+	defer p.exit()
+
+	select {
+	case <-(p.done):
+		return p.result("Poll"), true
+	default:
+	}
+	return
+}
+
+// result returns the set result. One-shot futures hand-off the result
+// once; subsequent calls (by op) return an ErrAlreadyConsumed error result.
+func (p *futureResult[T]) result(op string) (r TypedResult[T]) {
+	if !p.broadcast && p.consumed.Swap(true) {
+		return &result[T]{e: &StateError{op, ErrAlreadyConsumed}, isError: true}
+	}
+	return p.r
+}
+
+// interface: future.Canceller#Cancel
+func (f *futureResult[T]) Cancel(cause error) bool {
+	return f.cancelGen(f.gen.Load(), cause)
+}
+
+// cancelGen is Cancel of generation gen.
+func (f *futureResult[T]) cancelGen(gen uint64, cause error) bool {
+	f.mu.Lock()
+	if f.finalized || f.stale(gen) {
+		f.mu.Unlock()
+		return false
+	}
+	err := &CancelledError{Cause: cause}
+	f.err = err
+	if f.cancelled != nil {
+		close(f.cancelled)
+	}
+	notify := f.set(&result[T]{e: err, isError: true})
+	f.mu.Unlock()
+
+	if notify {
+		f.notify()
+	}
+	return true
+}
+
+// ______________________________________________________________________
+// support for future.CallbackFuture
+
+// interface: future.CallbackFuture#OnComplete
+func (f *futureResult[T]) OnComplete(fn func(r TypedResult[T])) {
+	f.onCompleteGen(f.gen.Load(), fn)
+}
+
+// onCompleteGen is OnComplete of generation gen. Callbacks registered with
+// a recycled future are run immediately, with an ErrRecycled error result.
+func (f *futureResult[T]) onCompleteGen(gen uint64, fn func(r TypedResult[T])) {
+	f.mu.Lock()
+	if f.stale(gen) {
+		f.mu.Unlock()
+		callback(fn, recycled[T]("OnComplete"))
+		return
+	}
+	f.callbacks = append(f.callbacks, fn)
+	notify := f.finalized && !f.notifying
+	if notify {
+		f.notifying = true
+	}
+	f.mu.Unlock()
+
+	if notify {
+		f.notify()
+	}
+}
+
+// interface: future.CallbackFuture#OnSuccess
+func (f *futureResult[T]) OnSuccess(fn func(v T)) {
+	f.OnComplete(func(r TypedResult[T]) {
+		if !r.IsError() {
+			fn(r.Value())
+		}
+	})
+}
+
+// interface: future.CallbackFuture#OnFailure
+func (f *futureResult[T]) OnFailure(fn func(e error)) {
+	f.OnComplete(func(r TypedResult[T]) {
+		if r.IsError() {
+			fn(r.Error())
+		}
+	})
+}
+
+// notify runs the pending callbacks, in order of registration, until none
+// remain. Only one goroutine (per f.notifying) runs the callbacks at a time.
+func (f *futureResult[T]) notify() {
+	for {
+		f.mu.Lock()
+		callbacks := f.callbacks
+		f.callbacks = nil
+		if len(callbacks) == 0 {
+			f.notifying = false
+			f.mu.Unlock()
+			return
+		}
+		f.mu.Unlock()
+
+		for _, fn := range callbacks {
+			callback(fn, f.r)
+		}
+	}
+}
+
+// callback invokes fn with r, recovering from (and discarding) any panic
+// so that a faulty callback does not prevent the others from running.
+func callback[T any](fn func(TypedResult[T]), r TypedResult[T]) {
+	defer func() { recover() }()
+	fn(r)
+}
+
 // ______________________________________________________________________
 // support for future.Provider
 
-func (f *futureResult) SetError(e error) error {
-	if f.finalized {
-		return errors.New("illegal state @ setError: already set")
+// interface: future.Provider#SetError
+func (f *futureResult[T]) SetError(e error) error {
+	if e == nil {
+		return &StateError{"SetError", ErrNilValue}
 	}
-	f.set(&result{e, true})
-	return nil
+	return f.complete("SetError", &result[T]{e: e, isError: true})
 }
 
-func (f *futureResult) SetValue(v interface{}) error {
-	if f.finalized {
-		return errors.New("illegal state @ setValue: already set")
+// setErrorGen is SetError of generation gen.
+func (f *futureResult[T]) setErrorGen(gen uint64, e error) error {
+	if e == nil {
+		return &StateError{"SetError", ErrNilValue}
+	}
+	return f.completeGen("SetError", gen, &result[T]{e: e, isError: true})
+}
+
+// interface: future.Provider#SetValue
+func (f *futureResult[T]) SetValue(v T) error {
+	if f.nilable && isNil(v) {
+		return &StateError{"SetValue", ErrNilValue}
+	}
+	return f.complete("SetValue", &result[T]{v: v})
+}
+
+// setValueGen is SetValue of generation gen.
+func (f *futureResult[T]) setValueGen(gen uint64, v T) error {
+	if f.nilable && isNil(v) {
+		return &StateError{"SetValue", ErrNilValue}
+	}
+	return f.completeGen("SetValue", gen, &result[T]{v: v})
+}
+
+// interface: future.Provider#Cancelled
+func (f *futureResult[T]) Cancelled() <-chan struct{} {
+	return f.cancelledGen(f.gen.Load())
+}
+
+// cancelledGen is Cancelled of generation gen. A recycled future is no
+// longer required by its consumer, and reads as cancelled.
+func (f *futureResult[T]) cancelledGen(gen uint64) <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.stale(gen) {
+		return closedChan
+	}
+	if f.cancelled == nil {
+		if f.err != nil {
+			return closedChan
+		}
+		f.cancelled = make(chan struct{})
+	}
+	return f.cancelled
+}
+
+// interface: future.Provider#Err
+func (f *futureResult[T]) Err() error {
+	return f.errGen(f.gen.Load())
+}
+
+// errGen is Err of generation gen.
+func (f *futureResult[T]) errGen(gen uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.stale(gen) {
+		return &StateError{"Err", ErrRecycled}
+	}
+	return f.err
+}
+
+// complete sets the result of the future if it is not already set.
+// Safe for concurrent use - exactly one call (of complete or Cancel) wins.
+func (f *futureResult[T]) complete(op string, r TypedResult[T]) error {
+	return f.completeGen(op, f.gen.Load(), r)
+}
+
+// completeGen is complete of generation gen.
+func (f *futureResult[T]) completeGen(op string, gen uint64, r TypedResult[T]) error {
+	f.mu.Lock()
+	switch {
+	case f.stale(gen):
+		f.mu.Unlock()
+		return &StateError{op, ErrRecycled}
+	case f.err != nil:
+		f.mu.Unlock()
+		return &StateError{op, ErrCancelled}
+	case f.finalized:
+		f.mu.Unlock()
+		return &StateError{op, ErrAlreadySet}
+	}
+	notify := f.set(r)
+	f.mu.Unlock()
+
+	if notify {
+		f.notify()
 	}
-	f.set(&result{v, false})
 	return nil
 }
 
-func (f *futureResult) set(r Result) {
-	f.rchan <- r
+// set hands-off the result to the consumer(s). caller must hold f.mu.
+// Returns true if the caller must (after releasing f.mu) run the callbacks.
+func (f *futureResult[T]) set(r TypedResult[T]) (notify bool) {
+	f.r = r
 	f.finalized = true
-	close(f.rchan)
+	close(f.done)
+
+	notify = len(f.callbacks) > 0 && !f.notifying
+	if notify {
+		f.notifying = true
+	}
+	return
+}
+
+// ______________________________________________________________________
+// support for reuse - see future.Pool
+
+// Reset readies a completed future for reuse, as if newly created with the
+// same options. It fails with an error that errors.Is future.ErrInUse if
+// the future is not completed, or, if it is still in use by a consumer:
+// a consumer is waiting on it, or (for one-shot futures) the result has
+// not been consumed, or its callbacks are running.
+//
+// Reset must only be called by the owner of the future, once all consumers
+// (and providers) are done with it; see future.Pool.
+func (f *futureResult[T]) Reset() error {
+	if e := f.recycle(f.gen.Load()); e != nil {
+		return e
+	}
+	f.pooled.Store(false)
+	return nil
+}
+
+// recycle resets the future of generation gen, leaving it in the pooled
+// state, and bumping its generation: any use of the future by a stale
+// consumer (or provider) of generation gen fails with ErrRecycled, even
+// once the future is reused.
+func (f *futureResult[T]) recycle(gen uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case f.stale(gen):
+		return &StateError{"Reset", ErrRecycled}
+	case !f.finalized, f.notifying, len(f.callbacks) > 0:
+		return &StateError{"Reset", ErrInUse}
+	case !f.broadcast && !f.consumed.Load():
+		return &StateError{"Reset", ErrInUse}
+	}
+
+	// see enter - either a consumer observes pooled, or waiting is observed
+	f.pooled.Store(true)
+	if f.waiting.Load() != 0 {
+		f.pooled.Store(false)
+		return &StateError{"Reset", ErrInUse}
+	}
+
+	f.gen.Add(1)
+	f.done = make(chan struct{})
+	f.r = nil
+	f.finalized = false
+	f.consumed.Store(false)
+	f.cancelled = nil
+	f.err = nil
+	return nil
+}
+
+// stale returns true if the future is recycled, or reused, since
+// generation gen.
+func (p *futureResult[T]) stale(gen uint64) bool {
+	return p.pooled.Load() || p.gen.Load() != gen
+}
+
+// enter registers a consumer of generation gen, returning false if the
+// future is stale. A successful enter must be followed by a call to exit.
+func (p *futureResult[T]) enter(gen uint64) bool {
+	p.waiting.Add(1)
+	if p.stale(gen) {
+		p.waiting.Add(-1)
+		return false
+	}
+	return true
+}
+
+// exit unregisters a consumer.
+func (p *futureResult[T]) exit() {
+	p.waiting.Add(-1)
+}
+
+// recycled returns the error result of consumer op of a recycled future.
+func recycled[T any](op string) TypedResult[T] {
+	return &result[T]{e: &StateError{op, ErrRecycled}, isError: true}
+}
+
+// ______________________________________________________________________
+// support for nil values
+
+// nilable returns true if values of type T can be nil.
+func nilable[T any]() bool {
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.UnsafePointer,
+		reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return true
+	}
+	return false
+}
+
+// isNil returns true if v (of a nilable type T) is nil.
+func isNil[T any](v T) bool {
+	var i interface{} = v
+	if i == nil {
+		return true
+	}
+	rv := reflect.ValueOf(i)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer,
+		reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// ______________________________________________________________________
+// support for TryGet timers and Cancelled
+
+// a closed channel - Cancelled of a future cancelled before the first call.
+var closedChan = make(chan struct{})
+
+func init() {
+	close(closedChan)
+}
+
+// pool of stopped timers, obviating a timer allocation per TryGet.
+var timerPool sync.Pool
+
+// getTimer returns a (pooled) timer set to fire after d.
+func getTimer(d time.Duration) *time.Timer {
+	if t, ok := timerPool.Get().(*time.Timer); ok {
+		t.Reset(d)
+		return t
+	}
+	return time.NewTimer(d)
+}
+
+// putTimer stops the timer, drains its channel, and returns it to the pool.
+func putTimer(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	timerPool.Put(t)
 }
@@ -4,7 +4,12 @@ package future
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -32,6 +37,18 @@ func testSpec() testspec {
 	return testspec
 }
 
+// waitFor polls cond until true, or fails the test after a second.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
 /// tests //////////////////////////////////////////////////////////////
 
 // ____________________________________________________________________
@@ -42,7 +59,11 @@ func testSpec() testspec {
 // MUST return timeout of true
 // MUST return value of nil
 func TestFutureContractNew(t *testing.T) {
-	futureObj := NewUntypedFuture()
+	testContract(t, testFutureContractNew)
+}
+
+func testFutureContractNew(t *testing.T, newFuture func(...Option) contractFuture) {
+	futureObj := newFuture()
 
 	// note: wait value is irrelevant in this test
 	// any value is fine
@@ -61,11 +82,15 @@ func TestFutureContractNew(t *testing.T) {
 // MUST return error of nil
 // MUST return value equal to spec data
 func TestFutureSetNonErrorValueThenTryGet(t *testing.T) {
+	testContract(t, testFutureSetNonErrorValueThenTryGet)
+}
+
+func testFutureSetNonErrorValueThenTryGet(t *testing.T, newFuture func(...Option) contractFuture) {
 	// test spec & data
 	test := testSpec()
 
 	// create & set the future result
-	futureObj := NewUntypedFuture()
+	futureObj := newFuture()
 	futureObj.SetValue(test.data)
 
 	// note: test.wait value is irrelevant in this test
@@ -96,11 +121,15 @@ func TestFutureSetNonErrorValueThenTryGet(t *testing.T) {
 // MUST return error result
 // MUST return error value equal to spec error
 func TestFutureSetErrorValueThenTryGet(t *testing.T) {
+	testContract(t, testFutureSetErrorValueThenTryGet)
+}
+
+func testFutureSetErrorValueThenTryGet(t *testing.T, newFuture func(...Option) contractFuture) {
 	// test spec & data
 	test := testSpec()
 
 	// create & set the future result
-	futureObj := NewUntypedFuture()
+	futureObj := newFuture()
 	futureObj.SetError(test.err)
 
 	// note: test.wait value is irrelevant in this test
@@ -134,10 +163,14 @@ func TestFutureSetErrorValueThenTryGet(t *testing.T) {
 // MUST return value equal to data
 //
 func TestFutureGetDelayThenSet(t *testing.T) {
+	testContract(t, testFutureGetDelayThenSet)
+}
+
+func testFutureGetDelayThenSet(t *testing.T, newFuture func(...Option) contractFuture) {
 
 	test := testSpec()
 
-	futureObj := NewUntypedFuture()
+	futureObj := newFuture()
 
 	// test go routine will block on Get until
 	// value is set. Result will be sent on rch
@@ -186,10 +219,14 @@ func TestFutureGetDelayThenSet(t *testing.T) {
 // MUST return value equal to data
 //
 func TestFutureWaitGetThenSet(t *testing.T) {
+	testContract(t, testFutureWaitGetThenSet)
+}
+
+func testFutureWaitGetThenSet(t *testing.T, newFuture func(...Option) contractFuture) {
 
 	test := testSpec()
 
-	futureObj := NewUntypedFuture()
+	futureObj := newFuture()
 
 	// test go routine will block on Get until
 	// value is set. Result will be sent on rch
@@ -237,10 +274,14 @@ func TestFutureWaitGetThenSet(t *testing.T) {
 // MUST return value equal to data
 //
 func TestFutureTryGetDelayThenSetBeforeTimeout(t *testing.T) {
+	testContract(t, testFutureTryGetDelayThenSetBeforeTimeout)
+}
+
+func testFutureTryGetDelayThenSetBeforeTimeout(t *testing.T, newFuture func(...Option) contractFuture) {
 
 	test := testSpec()
 
-	futureObj := NewUntypedFuture()
+	futureObj := newFuture()
 
 	// test go routine will block on Get until
 	// value is set. Result will be sent on rch
@@ -285,3 +326,781 @@ func TestFutureTryGetDelayThenSetBeforeTimeout(t *testing.T) {
 		}
 	}
 }
+
+// ____________________________________________________________________
+// typed futures
+
+// timed call to initialized (set) typed future value
+// expecting data, no error and no timeout
+// MUST return value of type T without any type assertions
+func TestTypedFutureSetValueThenGet(t *testing.T) {
+	testTypedContract(t, testTypedFutureSetValueThenGet)
+}
+
+func testTypedFutureSetValueThenGet(t *testing.T, newFuture func(...Option) typedContractFuture[[]byte]) {
+	test := testSpec()
+
+	futureObj := newFuture()
+	if e := futureObj.SetValue(test.data); e != nil {
+		t.Fatalf("unexpected SetValue error: %s", e)
+	}
+
+	// note: explict cast not required
+	// being explicit to clarify semantics
+	var future TypedFuture[[]byte] = futureObj
+	result := future.Get()
+	switch {
+	case result.IsError():
+		t.Error("expected IsError => false")
+	case result.Error() != nil:
+		t.Error("exected Error() => nil")
+	case bytes.Compare(result.Value(), test.data) != 0:
+		t.Error("unexpected result value")
+	}
+}
+
+// timed call to initialized (set) typed future error
+// MUST return zero-value of type T on error
+func TestTypedFutureSetErrorThenTryGet(t *testing.T) {
+	testTypedContract(t, testTypedFutureSetErrorThenTryGet)
+}
+
+func testTypedFutureSetErrorThenTryGet(t *testing.T, newFuture func(...Option) typedContractFuture[int]) {
+	test := testSpec()
+
+	futureObj := newFuture()
+	futureObj.SetError(test.err)
+
+	result, timeout := futureObj.TryGet(test.wait)
+	switch {
+	case timeout:
+		t.Error("exected timeout => false")
+	case !result.IsError():
+		t.Error("expected IsError => true")
+	case result.Error() != test.err:
+		t.Error("unexpected result error value")
+	case result.Value() != 0:
+		t.Error("expected zero-value Value()")
+	}
+}
+
+// untyped api is the T = interface{} case of the typed api.
+func TestUntypedIsTypedInterface(t *testing.T) {
+	var _ TypedFuture[interface{}] = NewUntypedFuture()
+	var _ Future = NewFuture[interface{}]()
+	var _ Provider = NewFuture[interface{}]()
+	var _ TypedProvider[string] = NewFuture[string]()
+}
+
+// ____________________________________________________________________
+// context
+
+// GetContext with cancelled context before the future value is set
+// MUST return the context cause and nil result
+// MAY be retried, per TryGet timeout semantics
+func TestFutureGetContextCancelThenRetry(t *testing.T) {
+	testContract(t, testFutureGetContextCancelThenRetry)
+}
+
+func testFutureGetContextCancelThenRetry(t *testing.T, newFuture func(...Option) contractFuture) {
+	test := testSpec()
+
+	futureObj := newFuture()
+	var _ ContextFuture = futureObj
+
+	cause := fmt.Errorf("client went away")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(cause)
+
+	result, e := futureObj.GetContext(ctx)
+	switch {
+	case e != cause:
+		t.Errorf("expected context cause error - got %v", e)
+	case result != nil:
+		t.Error("expected nil result on context done")
+	}
+
+	// retry with a live context after value is set
+	futureObj.SetValue(test.data)
+	result, e = futureObj.GetContext(context.Background())
+	switch {
+	case e != nil:
+		t.Errorf("unexpected error %s", e)
+	case result == nil:
+		t.Error("expected non-nil result")
+	case bytes.Compare(result.Value().([]byte), test.data) != 0:
+		t.Error("unexpected result value")
+	}
+}
+
+// GetContext with a deadline that expires before the value is set
+// MUST return context.DeadlineExceeded
+func TestFutureGetContextDeadline(t *testing.T) {
+	testTypedContract(t, testFutureGetContextDeadline)
+}
+
+func testFutureGetContextDeadline(t *testing.T, newFuture func(...Option) typedContractFuture[[]byte]) {
+	test := testSpec()
+
+	futureObj := newFuture()
+	ctx, cancel := context.WithTimeout(context.Background(), test.providerDelay)
+	defer cancel()
+
+	if _, e := futureObj.GetContext(ctx); e != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded - got %v", e)
+	}
+}
+
+// ____________________________________________________________________
+// cancellation
+
+// Cancel before the future value is set
+// MUST signal the provider via Cancelled & Err
+// MUST reject subsequent provider sets with ErrCancelled
+// MUST complete the future with an ErrCancelled error result
+func TestFutureCancelThenSet(t *testing.T) {
+	testContract(t, testFutureCancelThenSet)
+}
+
+func testFutureCancelThenSet(t *testing.T, newFuture func(...Option) contractFuture) {
+	test := testSpec()
+
+	futureObj := newFuture()
+	var _ Canceller = futureObj
+
+	var provider Provider = futureObj
+	if provider.Err() != nil {
+		t.Error("expected nil Err() before cancel")
+	}
+
+	cause := fmt.Errorf("no longer needed")
+	if !futureObj.Cancel(cause) {
+		t.Fatal("expected Cancel => true")
+	}
+	if futureObj.Cancel(cause) {
+		t.Error("expected repeated Cancel => false")
+	}
+
+	select {
+	case <-provider.Cancelled():
+	default:
+		t.Error("expected Cancelled() channel to be closed")
+	}
+	if !errors.Is(provider.Err(), ErrCancelled) || !errors.Is(provider.Err(), cause) {
+		t.Errorf("expected Err() to be ErrCancelled with cause - got %v", provider.Err())
+	}
+	if e := provider.SetValue(test.data); !errors.Is(e, ErrCancelled) {
+		t.Errorf("expected SetValue => ErrCancelled - got %v", e)
+	}
+	if e := provider.SetError(test.err); !errors.Is(e, ErrCancelled) {
+		t.Errorf("expected SetError => ErrCancelled - got %v", e)
+	}
+
+	result := futureObj.Get()
+	switch {
+	case !result.IsError():
+		t.Error("expected IsError => true")
+	case !errors.Is(result.Error(), ErrCancelled):
+		t.Errorf("expected ErrCancelled result - got %v", result.Error())
+	case result.Value() != nil:
+		t.Error("expected Value() == nil")
+	}
+}
+
+// provider waiting on Cancelled before Cancel
+// MUST be signalled on Cancel
+func TestFutureCancelledWait(t *testing.T) {
+	testContract(t, testFutureCancelledWait)
+}
+
+func testFutureCancelledWait(t *testing.T, newFuture func(...Option) contractFuture) {
+	futureObj := newFuture()
+
+	cancelled := futureObj.Cancelled()
+	go futureObj.Cancel(nil)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected Cancelled() channel to be closed")
+	}
+}
+
+// Cancel after the future value is set
+// MUST return false and leave the result as set
+func TestFutureSetThenCancel(t *testing.T) {
+	testTypedContract(t, testFutureSetThenCancel)
+}
+
+func testFutureSetThenCancel(t *testing.T, newFuture func(...Option) typedContractFuture[[]byte]) {
+	test := testSpec()
+
+	futureObj := newFuture()
+	futureObj.SetValue(test.data)
+
+	if futureObj.Cancel(nil) {
+		t.Error("expected Cancel => false after set")
+	}
+	if futureObj.Err() != nil {
+		t.Error("expected nil Err() after failed cancel")
+	}
+	if result := futureObj.Get(); result.IsError() {
+		t.Errorf("unexpected error result %s", result.Error())
+	}
+}
+
+// ____________________________________________________________________
+// concurrent providers
+
+// concurrent SetValue | SetError | Cancel calls by many goroutines
+// MUST result in exactly one winner
+// MUST return ErrAlreadySet | ErrCancelled to all losers
+// MUST NOT panic or block
+// note: run with -race
+func TestFutureConcurrentSetStress(t *testing.T) {
+	testContract(t, testFutureConcurrentSetStress)
+}
+
+func testFutureConcurrentSetStress(t *testing.T, newFuture func(...Option) contractFuture) {
+	test := testSpec()
+
+	const rounds = 1000
+	const providers = 8
+
+	for i := 0; i < rounds; i++ {
+		futureObj := newFuture()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, providers)
+		for n := 0; n < providers; n++ {
+			wg.Add(1)
+			go func(n int) {
+				defer wg.Done()
+				var e error
+				switch n % 3 {
+				case 0:
+					e = futureObj.SetValue(test.data)
+				case 1:
+					e = futureObj.SetError(test.err)
+				default:
+					if !futureObj.Cancel(nil) {
+						e = ErrAlreadySet
+					}
+				}
+				errs <- e
+			}(n)
+		}
+		wg.Wait()
+		close(errs)
+
+		winners := 0
+		for e := range errs {
+			switch {
+			case e == nil:
+				winners++
+			case errors.Is(e, ErrAlreadySet), errors.Is(e, ErrCancelled):
+			default:
+				t.Fatalf("unexpected loser error %v", e)
+			}
+		}
+		if winners != 1 {
+			t.Fatalf("round %d: expected exactly 1 winner - got %d", i, winners)
+		}
+		if result, timeout := futureObj.TryGet(test.wait); timeout || result == nil {
+			t.Fatalf("round %d: expected result after concurrent set", i)
+		}
+	}
+}
+
+// concurrent provider sets racing a blocked consumer
+// MUST deliver exactly one result to the consumer
+func TestFutureConcurrentSetWithConsumer(t *testing.T) {
+	testTypedContract(t, testFutureConcurrentSetWithConsumer)
+}
+
+func testFutureConcurrentSetWithConsumer(t *testing.T, newFuture func(...Option) typedContractFuture[int]) {
+	test := testSpec()
+
+	for i := 0; i < 100; i++ {
+		futureObj := newFuture()
+
+		rch := make(chan TypedResult[int])
+		go func() { rch <- futureObj.Get() }()
+
+		var wg sync.WaitGroup
+		for n := 0; n < 4; n++ {
+			wg.Add(1)
+			go func(n int) {
+				defer wg.Done()
+				if n%2 == 0 {
+					futureObj.SetValue(n)
+				} else {
+					futureObj.SetError(test.err)
+				}
+			}(n)
+		}
+		wg.Wait()
+
+		select {
+		case result := <-rch:
+			if result == nil {
+				t.Fatal("expected non-nil result")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected result for Get by now")
+		}
+	}
+}
+
+// ____________________________________________________________________
+// broadcast
+
+// Get | TryGet by many consumers of a broadcast future
+// MUST all return the same result
+func TestBroadcastFutureManyConsumers(t *testing.T) {
+	testContract(t, testBroadcastFutureManyConsumers)
+}
+
+func testBroadcastFutureManyConsumers(t *testing.T, newFuture func(...Option) contractFuture) {
+	test := testSpec()
+
+	futureObj := newFuture(Broadcast())
+
+	const consumers = 16
+	rch := make(chan Result, consumers)
+	for n := 0; n < consumers; n++ {
+		go func(n int, future Future) {
+			if n%2 == 0 {
+				rch <- future.Get()
+				return
+			}
+			for {
+				if result, timeout := future.TryGet(test.wait); !timeout {
+					rch <- result
+					return
+				}
+			}
+		}(n, futureObj)
+	}
+
+	time.Sleep(test.providerDelay)
+	futureObj.SetValue(test.data)
+
+	var first Result
+	for n := 0; n < consumers; n++ {
+		select {
+		case result := <-rch:
+			switch {
+			case result == nil:
+				t.Fatal("expected non-nil result")
+			case first == nil:
+				first = result
+			case result != first:
+				t.Error("expected the same result for all consumers")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected result for Get by now")
+		}
+	}
+
+	// and subsequent calls after the fact
+	if futureObj.Get() != first {
+		t.Error("expected the same result for subsequent Get")
+	}
+}
+
+// repeated Get of a (default) one-shot future
+// MUST hand-off the result exactly once
+// MUST return ErrAlreadyConsumed error result for subsequent calls
+func TestOneShotFutureRepeatedGet(t *testing.T) {
+	testContract(t, testOneShotFutureRepeatedGet)
+}
+
+func testOneShotFutureRepeatedGet(t *testing.T, newFuture func(...Option) contractFuture) {
+	test := testSpec()
+
+	futureObj := newFuture()
+	futureObj.SetValue(test.data)
+
+	if result := futureObj.Get(); result == nil {
+		t.Fatal("expected non-nil result for first Get")
+	}
+	result, timeout := futureObj.TryGet(test.wait)
+	var se *StateError
+	switch {
+	case timeout:
+		t.Fatal("exected timeout => false")
+	case !errors.Is(result.Error(), ErrAlreadyConsumed):
+		t.Errorf("expected ErrAlreadyConsumed - got %v", result.Error())
+	case !errors.As(result.Error(), &se) || se.Op != "TryGet":
+		t.Errorf("expected *StateError for TryGet - got %v", result.Error())
+	}
+}
+
+// ____________________________________________________________________
+// select
+
+// Done & Poll of a future in a select with other channels
+// MUST NOT be selectable (or pollable) before set
+// MUST be selectable and pollable after set
+func TestFutureDoneSelect(t *testing.T) {
+	testContract(t, testFutureDoneSelect)
+}
+
+func testFutureDoneSelect(t *testing.T, newFuture func(...Option) contractFuture) {
+	test := testSpec()
+
+	futureObj := newFuture()
+	var future SelectableFuture = futureObj
+
+	if _, ok := future.Poll(); ok {
+		t.Error("expected Poll => false before set")
+	}
+
+	shutdown := make(chan struct{})
+	select {
+	case <-future.Done():
+		t.Fatal("unexpected Done before set")
+	case <-shutdown:
+	default:
+	}
+
+	go func() {
+		time.Sleep(test.providerDelay)
+		futureObj.SetValue(test.data)
+	}()
+
+	select {
+	case <-future.Done():
+		result, ok := future.Poll()
+		switch {
+		case !ok:
+			t.Error("expected Poll => true after Done")
+		case bytes.Compare(result.Value().([]byte), test.data) != 0:
+			t.Error("unexpected result value")
+		}
+	case <-shutdown:
+		t.Fatal("unexpected shutdown")
+	case <-time.After(time.Second):
+		t.Fatal("expected Done by now")
+	}
+}
+
+// reflect.Select over many futures
+// MUST select the completed future
+func TestFutureDoneReflectSelect(t *testing.T) {
+	testTypedContract(t, testFutureDoneReflectSelect)
+}
+
+func testFutureDoneReflectSelect(t *testing.T, newFuture func(...Option) typedContractFuture[int]) {
+	futures := make([]typedContractFuture[int], 8)
+	cases := make([]reflect.SelectCase, len(futures))
+	for i := range futures {
+		futures[i] = newFuture()
+		cases[i] = reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(futures[i].Done()),
+		}
+	}
+
+	futures[5].SetValue(5)
+
+	chosen, _, _ := reflect.Select(cases)
+	if chosen != 5 {
+		t.Fatalf("expected future 5 selected - got %d", chosen)
+	}
+	if result, ok := futures[chosen].Poll(); !ok || result.Value() != 5 {
+		t.Error("unexpected result for selected future")
+	}
+}
+
+// ____________________________________________________________________
+// callbacks
+
+// callbacks registered before set
+// MUST run on set, in order of registration
+// MUST run despite a preceding callback panic
+// MUST NOT consume the result of a one-shot future
+func TestFutureCallbacksOrderAndPanic(t *testing.T) {
+	testContract(t, testFutureCallbacksOrderAndPanic)
+}
+
+func testFutureCallbacksOrderAndPanic(t *testing.T, newFuture func(...Option) contractFuture) {
+	test := testSpec()
+
+	futureObj := newFuture()
+	var future CallbackFuture = futureObj
+
+	var order []int
+	future.OnComplete(func(r Result) { order = append(order, 1) })
+	future.OnComplete(func(r Result) { panic("callback 2") })
+	future.OnSuccess(func(v interface{}) { order = append(order, 3) })
+	future.OnFailure(func(e error) { order = append(order, -1) })
+	future.OnComplete(func(r Result) { order = append(order, 4) })
+
+	if len(order) != 0 {
+		t.Fatal("unexpected callbacks before set")
+	}
+	futureObj.SetValue(test.data)
+
+	if fmt.Sprint(order) != "[1 3 4]" {
+		t.Errorf("unexpected callback order %v", order)
+	}
+	if result, ok := futureObj.Poll(); !ok || result == nil {
+		t.Error("expected result to remain available for consumer")
+	}
+}
+
+// callbacks registered after set | cancel
+// MUST run immediately
+func TestFutureCallbacksAfterCompletion(t *testing.T) {
+	testTypedContract(t, testFutureCallbacksAfterCompletion)
+}
+
+func testFutureCallbacksAfterCompletion(t *testing.T, newFuture func(...Option) typedContractFuture[int]) {
+	test := testSpec()
+
+	futureObj := newFuture()
+	futureObj.SetError(test.err)
+
+	var err error
+	futureObj.OnSuccess(func(v int) { t.Error("unexpected OnSuccess") })
+	futureObj.OnFailure(func(e error) { err = e })
+	if err != test.err {
+		t.Errorf("expected OnFailure with spec error - got %v", err)
+	}
+
+	cancelled := newFuture()
+	cancelled.OnFailure(func(e error) { err = e })
+	cancelled.Cancel(nil)
+	if !errors.Is(err, ErrCancelled) {
+		t.Errorf("expected OnFailure with ErrCancelled - got %v", err)
+	}
+}
+
+// callbacks registered concurrently with set
+// MUST all run exactly once
+func TestFutureCallbacksConcurrent(t *testing.T) {
+	testTypedContract(t, testFutureCallbacksConcurrent)
+}
+
+func testFutureCallbacksConcurrent(t *testing.T, newFuture func(...Option) typedContractFuture[int]) {
+	for i := 0; i < 100; i++ {
+		futureObj := newFuture()
+
+		const callbacks = 8
+		var n atomic.Int32
+		var wg sync.WaitGroup
+		for c := 0; c < callbacks; c++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				futureObj.OnSuccess(func(v int) { n.Add(1) })
+			}()
+		}
+		futureObj.SetValue(i)
+		wg.Wait()
+
+		if n.Load() != callbacks {
+			t.Fatalf("expected %d callbacks - got %d", callbacks, n.Load())
+		}
+	}
+}
+
+// ____________________________________________________________________
+// nil values
+
+// SetValue | SetError with nil (and non-nil) values, per future type & options
+// MUST reject nil values with ErrNilValue, unless AllowNil
+// MUST reject nil errors with ErrNilValue, regardless of AllowNil
+// MUST NOT set the future on rejection
+func TestFutureNilContract(t *testing.T) {
+	test := testSpec()
+
+	var nilptr *testspec
+	var nilmap map[string]int
+	var nilfunc func()
+
+	for impl, c := range contractImpls {
+		name := c.name
+		for _, tc := range []struct {
+			name     string
+			provider func(impl int) (f interface{}, isSet func() bool)
+			set      func(f interface{}) error
+			rejected bool
+		}{
+			{"untyped nil value", newUntyped(), setValue(nil), true},
+			{"untyped nil ptr value", newUntyped(), setValue(nilptr), true},
+			{"untyped value", newUntyped(), setValue(test.data), false},
+			{"untyped nil error", newUntyped(), setError(nil), true},
+			{"untyped error", newUntyped(), setError(test.err), false},
+			{"untyped AllowNil nil value", newUntyped(AllowNil()), setValue(nil), false},
+			{"untyped AllowNil nil error", newUntyped(AllowNil()), setError(nil), true},
+
+			{"typed nil ptr", newTyped[*testspec](), setTyped(nilptr), true},
+			{"typed nil map", newTyped[map[string]int](), setTyped(nilmap), true},
+			{"typed nil func", newTyped[func()](), setTyped(nilfunc), true},
+			{"typed nil slice", newTyped[[]byte](), setTyped([]byte(nil)), true},
+			{"typed empty slice", newTyped[[]byte](), setTyped([]byte{}), false},
+			{"typed nil error iface", newTyped[error](), setTyped(error(nil)), true},
+			{"typed zero int", newTyped[int](), setTyped(0), false},
+			{"typed zero struct", newTyped[testspec](), setTyped(testspec{}), false},
+			{"typed AllowNil nil ptr", newTyped[*testspec](AllowNil()), setTyped(nilptr), false},
+		} {
+			f, isSet := tc.provider(impl)
+			e := tc.set(f)
+			timeout := !isSet()
+
+			switch {
+			case tc.rejected && !errors.Is(e, ErrNilValue):
+				t.Errorf("%s/%s: expected ErrNilValue - got %v", name, tc.name, e)
+			case tc.rejected && !timeout:
+				t.Errorf("%s/%s: expected future not to be set on rejection", name, tc.name)
+			case !tc.rejected && e != nil:
+				t.Errorf("%s/%s: unexpected error %v", name, tc.name, e)
+			case !tc.rejected && timeout:
+				t.Errorf("%s/%s: expected future to be set", name, tc.name)
+			}
+		}
+	}
+}
+
+// test helpers for TestFutureNilContract
+
+func newUntyped(opts ...Option) func(impl int) (interface{}, func() bool) {
+	return newTyped[interface{}](opts...)
+}
+
+func newTyped[T any](opts ...Option) func(impl int) (interface{}, func() bool) {
+	return func(impl int) (interface{}, func() bool) {
+		f := typedContractImpls[T]()[impl].newFuture(opts...)
+		return f, func() bool { _, timeout := f.TryGet(0); return !timeout }
+	}
+}
+
+func setValue(v interface{}) func(f interface{}) error {
+	return func(f interface{}) error { return f.(Provider).SetValue(v) }
+}
+
+func setError(e error) func(f interface{}) error {
+	return func(f interface{}) error { return f.(Provider).SetError(e) }
+}
+
+func setTyped[T any](v T) func(f interface{}) error {
+	return func(f interface{}) error { return f.(TypedProvider[T]).SetValue(v) }
+}
+
+// ____________________________________________________________________
+// TryGet timers
+
+// TryGet with zero (or negative) wait of a set future
+// MUST NOT timeout
+func TestFutureTryGetZeroWait(t *testing.T) {
+	testContract(t, testFutureTryGetZeroWait)
+}
+
+func testFutureTryGetZeroWait(t *testing.T, newFuture func(...Option) contractFuture) {
+	test := testSpec()
+
+	for i := 0; i < 100; i++ {
+		futureObj := newFuture()
+		if _, timeout := futureObj.TryGet(0); !timeout {
+			t.Fatal("expected timeout before set")
+		}
+		futureObj.SetValue(test.data)
+		if result, timeout := futureObj.TryGet(0); timeout || result == nil {
+			t.Fatal("expected result with zero wait after set")
+		}
+	}
+}
+
+// TryGet of a set future
+// MUST NOT allocate
+func TestFutureTryGetAllocs(t *testing.T) {
+	testContract(t, testFutureTryGetAllocs)
+}
+
+func testFutureTryGetAllocs(t *testing.T, newFuture func(...Option) contractFuture) {
+	test := testSpec()
+
+	futureObj := newFuture(Broadcast())
+	futureObj.SetValue(test.data)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		futureObj.TryGet(test.wait)
+	})
+	if allocs != 0 {
+		t.Errorf("expected 0 allocs per TryGet - got %v", allocs)
+	}
+}
+
+/// benchmarks /////////////////////////////////////////////////////////
+
+// TryGet of a set future - fast path
+func BenchmarkFutureTryGetSet(b *testing.B) {
+	test := testSpec()
+
+	futureObj := NewUntypedFuture(Broadcast())
+	futureObj.SetValue(test.data)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		futureObj.TryGet(test.wait)
+	}
+}
+
+// TryGet retries of a future not set - timer path
+func BenchmarkFutureTryGetTimeout(b *testing.B) {
+	futureObj := NewUntypedFuture()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		futureObj.TryGet(time.Nanosecond)
+	}
+}
+
+// baseline: select over a bare channel w/ time.After (per comparative/)
+func BenchmarkChannelTimeAfterTimeout(b *testing.B) {
+	c := make(chan Result, 1)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		select {
+		case <-c:
+		case <-time.After(time.Nanosecond):
+		}
+	}
+}
+
+// request/response hand-off between goroutines via TryGet retries
+func BenchmarkFutureHandOff(b *testing.B) {
+	test := testSpec()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		futureObj := NewUntypedFuture()
+		go futureObj.SetValue(test.data)
+		for {
+			if _, timeout := futureObj.TryGet(time.Microsecond); !timeout {
+				break
+			}
+		}
+	}
+}
+
+// baseline: request/response hand-off via a bare channel (per comparative/)
+func BenchmarkChannelHandOff(b *testing.B) {
+	test := testSpec()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c := make(chan interface{}, 1)
+		go func() { c <- test.data }()
+	retry:
+		select {
+		case <-c:
+		case <-time.After(time.Microsecond):
+			goto retry
+		}
+	}
+}